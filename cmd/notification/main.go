@@ -3,17 +3,23 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// часовые пояса пользователей нужны для тихих часов, а в образе alpine нет tzdata
+	_ "time/tzdata"
 
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/adapter/email"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/adapter/kafka"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/auth"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
 	database "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/db"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
 	httpDelivery "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/handler/http"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/realtime"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/repository/postgres"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/usecase"
 )
@@ -45,35 +51,216 @@ func main() {
 	log.Println("Email infrastructure initialized")
 
 	notificationRepo := postgres.NewNotificationRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	preferenceRepo := postgres.NewPreferenceRepository(db)
 	log.Println("Repository initialized")
 
-	emailUseCase := usecase.NewEmailNotificationUseCase(
+	// изменения уведомлений приходят через LISTEN/NOTIFY от всех реплик и раздаются локальным SSE подключениям
+	notificationHub := realtime.NewHub()
+	notificationRelay := realtime.NewRelay(notificationRepo, notificationHub)
+	notificationListener := database.NewNotificationListener(&cfg.Database,
+		func(ctx context.Context, change database.NotificationChange) {
+			notificationRelay.Relay(ctx, change.ID, change.UserID)
+		},
+	)
+	log.Println("Realtime delivery initialized")
+
+	outcomePublisher, err := kafka.NewOutcomePublisher(&cfg.Kafka)
+	if err != nil {
+		log.Fatalf("Failed to initialize outcome publisher: %v", err)
+	}
+	defer outcomePublisher.Close()
+
+	retryPolicy := usecase.NewRetryPolicy(&cfg.Retry)
+	preferenceUseCase := usecase.NewPreferenceUseCase(preferenceRepo)
+	emailDelivery := usecase.NewEmailDelivery(
+		notificationRepo,
+		emailSender,
+		preferenceUseCase,
+		templateRenderer,
+		retryPolicy,
+		cfg.Email.AppBaseURL,
+	)
+	emailUseCase := usecase.NewEmailNotificationUseCase(notificationRepo, preferenceUseCase)
+	chatUseCase := usecase.NewChatNotificationUseCase(notificationRepo, preferenceUseCase)
+	listingUseCase := usecase.NewListingNotificationUseCase(notificationRepo, preferenceUseCase)
+	reviewUseCase := usecase.NewReviewNotificationUseCase(notificationRepo, preferenceUseCase)
+	orderUseCase := usecase.NewOrderNotificationUseCase(notificationRepo, preferenceUseCase)
+	retryWorker := usecase.NewRetryWorker(notificationRepo, emailDelivery, &cfg.Retry)
+	notificationScheduler := usecase.NewNotificationScheduler(notificationRepo, &cfg.Scheduler)
+	// сводка копится часами, поэтому повторять ее через RETRY_BASE_DELAY слишком часто
+	digestRetryPolicy := usecase.NewRetryPolicy(&cfg.Retry)
+	digestRetryPolicy.BaseDelay = cfg.Digest.RetryDelay
+	digestWorker := usecase.NewDigestWorker(
 		notificationRepo,
 		emailSender,
+		preferenceUseCase,
 		templateRenderer,
+		digestRetryPolicy,
+		cfg.Email.AppBaseURL,
+		&cfg.Digest,
+	)
+	outboxDispatcher := usecase.NewOutboxDispatcher(
+		outboxRepo,
+		notificationRepo,
+		outcomePublisher,
+		retryPolicy,
+		&cfg.Outbox,
 	)
+	outboxDispatcher.RegisterChannel(domain.ChannelEmail, emailDelivery)
+	inboxUseCase := usecase.NewInboxUseCase(notificationRepo)
 	log.Println("Use case initialized")
 
-	kafkaHandler := kafka.NewNotificationHandler(emailUseCase)
-	kafkaConsumer := kafka.NewConsumer(&cfg.Kafka, kafkaHandler)
+	eventDeduplicator := usecase.NewEventDeduplicator(notificationRepo)
+	// у каждого топика свой набор событий и свой обработчик
+	userEvents := kafka.NewEventRegistry()
+	kafka.RegisterEmailVerification(userEvents, emailUseCase)
+	kafka.RegisterReviewReceived(userEvents, reviewUseCase)
+	chatEvents := kafka.NewEventRegistry()
+	kafka.RegisterChatMessage(chatEvents, chatUseCase)
+	listingEvents := kafka.NewEventRegistry()
+	kafka.RegisterListingUpdate(listingEvents, listingUseCase)
+	orderEvents := kafka.NewEventRegistry()
+	kafka.RegisterOrderEvents(orderEvents, orderUseCase)
+	// отмена принимается в любом топике: если она опередила событие из другого топика,
+	// репозиторий сохранит ее и применит при создании уведомлений
+	for _, registry := range []*kafka.EventRegistry{userEvents, chatEvents, listingEvents, orderEvents} {
+		kafka.RegisterNotificationCancel(registry, notificationScheduler)
+	}
+
+	eventSchemas, err := kafka.NewSchemaValidator(cfg.Kafka.SchemasPath)
+	if err != nil {
+		log.Fatalf("Failed to load event schemas: %v", err)
+	}
+
+	topicRouter := kafka.NewTopicRouter()
+	for topic, registry := range map[string]*kafka.EventRegistry{
+		cfg.Kafka.TopicUserEvents:    userEvents,
+		cfg.Kafka.TopicChatEvents:    chatEvents,
+		cfg.Kafka.TopicListingEvents: listingEvents,
+		cfg.Kafka.TopicOrderEvents:   orderEvents,
+	} {
+		if err := eventSchemas.Covers(registry.Types()); err != nil {
+			log.Fatalf("Event schemas are incomplete for topic %s: %v", topic, err)
+		}
+		topicRouter.Route(topic, kafka.NewNotificationHandler(
+			registry,
+			eventSchemas,
+			eventDeduplicator,
+			cfg.Kafka.UnknownEventsToDLQ,
+		))
+	}
+
+	deadLetters, err := kafka.NewDeadLetterPublisher(&cfg.Kafka)
+	if err != nil {
+		log.Fatalf("Failed to initialize DLQ publisher: %v", err)
+	}
+	defer deadLetters.Close()
+	failureRouter, err := kafka.NewFailureRouter(&cfg.Kafka, deadLetters)
+	if err != nil {
+		log.Fatalf("Failed to initialize failure router: %v", err)
+	}
+	defer failureRouter.Close()
+
+	// оффсеты сдвигаются до создания ридеров: kafka.NewReader сразу вступает в группу,
+	// а коммит вне группы брокер принимает, только пока в ней нет участников
+	var replayWindow *kafka.ReplayWindow
+	if cfg.Kafka.Replay.Enabled() {
+		replayWindow, err = kafka.ResetGroupForReplay(context.Background(), &cfg.Kafka)
+		if err != nil {
+			log.Fatalf("Failed to prepare replay of %s: %v", cfg.Kafka.Replay.Topic, err)
+		}
+		log.Printf("Replay of topic %s prepared", cfg.Kafka.Replay.Topic)
+	}
+
+	topicConsumers, err := kafka.NewConsumers(&cfg.Kafka, topicRouter, failureRouter)
+	if err != nil {
+		log.Fatalf("Failed to initialize Kafka consumers: %v", err)
+	}
+	retryConsumers, err := kafka.NewRetryConsumers(&cfg.Kafka, topicRouter, failureRouter)
+	if err != nil {
+		log.Fatalf("Failed to initialize Kafka retry consumers: %v", err)
+	}
+	kafkaConsumers := kafka.ConsumerSet(append(topicConsumers, retryConsumers...))
+
+	if replayWindow != nil {
+		for _, consumer := range topicConsumers {
+			if consumer.Topic() == replayWindow.Topic() {
+				consumer.LimitToReplay(replayWindow)
+			}
+		}
+	}
 	log.Println("Kafka consumer initialized")
 
-	router := httpDelivery.SetupRouter()
+	jwtVerifier, err := auth.NewJWTVerifier(&cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to initialize JWT verifier: %v", err)
+	}
+
+	httpHandler := httpDelivery.NewHandler(
+		inboxUseCase,
+		preferenceUseCase,
+		notificationHub,
+		kafkaConsumers,
+		cfg.Server.SSEHeartbeatInterval,
+	)
+	router := httpDelivery.SetupRouter(httpHandler, jwtVerifier)
 	srv := &http.Server{
 		Addr:         cfg.Server.Address,
 		Handler:      router,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
+	// SSE подключения никогда не простаивают, поэтому при остановке сервера закрываем их явно
+	streamsCtx, cancelStreams := context.WithCancel(context.Background())
+	srv.BaseContext = func(net.Listener) context.Context { return streamsCtx }
+	srv.RegisterOnShutdown(cancelStreams)
 	log.Println("HTTP server initialized")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	for _, kafkaConsumer := range kafkaConsumers {
+		go func() {
+			log.Printf("Starting Kafka consumer for %s", kafkaConsumer.Topic())
+			if err := kafkaConsumer.Start(ctx); err != nil {
+				log.Printf("Kafka consumer for %s stopped: %v", kafkaConsumer.Topic(), err)
+			}
+		}()
+	}
+
+	go func() {
+		log.Println("Starting outbox dispatcher")
+		if err := outboxDispatcher.Start(ctx); err != nil {
+			log.Printf("Outbox dispatcher stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		log.Println("Starting retry worker")
+		if err := retryWorker.Start(ctx); err != nil {
+			log.Printf("Retry worker stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		log.Println("Starting notification scheduler")
+		if err := notificationScheduler.Start(ctx); err != nil {
+			log.Printf("Notification scheduler stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		log.Println("Starting digest worker")
+		if err := digestWorker.Start(ctx); err != nil {
+			log.Printf("Digest worker stopped: %v", err)
+		}
+	}()
+
 	go func() {
-		log.Println("Starting Kafka consumer")
-		if err := kafkaConsumer.Start(ctx); err != nil {
-			log.Printf("Kafka consumer stopped: %v", err)
+		log.Println("Starting notification listener")
+		if err := notificationListener.Start(ctx); err != nil {
+			log.Printf("Notification listener stopped: %v", err)
 		}
 	}()
 
@@ -0,0 +1,38 @@
+#!/usr/bin/env sh
+# Самоподписанные сертификаты для локального Kafka с SASL_SSL.
+# Создает CA, keystore/truststore брокера (PKCS12) и PEM файлы для сервиса.
+set -eu
+
+DIR="$(cd "$(dirname "$0")" && pwd)/certs"
+PASSWORD="${KAFKA_CERTS_PASSWORD:-changeit}"
+BROKER_HOST="${KAFKA_BROKER_HOST:-kafka}"
+
+mkdir -p "$DIR"
+cd "$DIR"
+
+# CA
+openssl req -x509 -newkey rsa:2048 -nodes -days 365 \
+    -keyout ca.key -out ca.pem -subj "/CN=avigo-local-ca"
+
+# сертификат брокера, SAN покрывает адрес внутри docker сети и localhost
+openssl req -newkey rsa:2048 -nodes \
+    -keyout broker.key -out broker.csr -subj "/CN=${BROKER_HOST}"
+printf "subjectAltName=DNS:%s,DNS:localhost,IP:127.0.0.1\n" "$BROKER_HOST" > broker.ext
+openssl x509 -req -in broker.csr -CA ca.pem -CAkey ca.key -CAcreateserial \
+    -days 365 -extfile broker.ext -out broker.pem
+
+openssl pkcs12 -export -in broker.pem -inkey broker.key -certfile ca.pem \
+    -name kafka -out kafka.keystore.p12 -passout "pass:${PASSWORD}"
+keytool -importcert -noprompt -alias ca -file ca.pem \
+    -keystore kafka.truststore.p12 -storetype PKCS12 -storepass "${PASSWORD}"
+
+# клиентский сертификат сервиса, нужен только если брокер требует mTLS
+openssl req -newkey rsa:2048 -nodes \
+    -keyout client.key -out client.csr -subj "/CN=notification-service"
+openssl x509 -req -in client.csr -CA ca.pem -CAkey ca.key -CAcreateserial \
+    -days 365 -out client.pem
+
+printf "%s" "$PASSWORD" > credentials
+rm -f ./*.csr ./*.ext ./*.srl
+
+echo "Certificates written to $DIR"
@@ -2,8 +2,13 @@ package email
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"mime"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
+	"strings"
 
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
@@ -38,6 +43,11 @@ func (s *SMTPSender) Send(ctx context.Context, msg *domain.EmailMessage) error {
 		return fmt.Errorf("recipient email cannot be empty")
 	}
 
+	// перевод строки в адресе добавил бы в письмо чужие заголовки или получателей
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("recipient email %q contains line breaks", msg.To)
+	}
+
 	select {
 	case <-ctx.Done():
 		return fmt.Errorf("email send cancelled: %w", ctx.Err())
@@ -51,27 +61,55 @@ func (s *SMTPSender) Send(ctx context.Context, msg *domain.EmailMessage) error {
 
 	err := smtp.SendMail(addr, auth, s.from, []string{msg.To}, []byte(mimeMessage))
 	if err != nil {
-		return fmt.Errorf("failed to send email via SMTP to %s: %w", msg.To, err)
+		return fmt.Errorf("failed to send email via SMTP to %s: %w", msg.To, classifySMTPError(err))
 	}
 
 	return nil
 }
 
+// classifySMTPError - постоянными считаем только явные отказы сервера (5xx: нет такого ящика,
+// письмо отклонено), все остальное (4xx, таймауты, обрывы соединения) - временные ошибки
+func classifySMTPError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return err
+	}
+	return domain.NewTransientError(err)
+}
+
+// buildMIMEMessage пишет заголовки в фиксированном порядке, адреса форматируются по RFC 5322
 func (s *SMTPSender) buildMIMEMessage(msg *domain.EmailMessage) string {
-	from := fmt.Sprintf("%s <%s>", s.fromName, s.from)
-
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = msg.To
-	headers["Subject"] = msg.Subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	message := ""
-	for k, v := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+	from := mail.Address{Name: stripLineBreaks(s.fromName), Address: s.from}
+	to := mail.Address{Address: msg.To}
+
+	headers := []struct{ name, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", headerValue(msg.Subject)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/html; charset=UTF-8"},
 	}
-	message += "\r\n" + msg.HTMLBody
 
-	return message
+	var message strings.Builder
+	for _, header := range headers {
+		message.WriteString(header.name)
+		message.WriteString(": ")
+		message.WriteString(header.value)
+		message.WriteString("\r\n")
+	}
+	message.WriteString("\r\n")
+	message.WriteString(msg.HTMLBody)
+
+	return message.String()
+}
+
+// headerValue готовит текст для заголовка письма: переводы строк убираются, а не-ASCII текст
+// кодируется по RFC 2047
+func headerValue(value string) string {
+	return mime.QEncoding.Encode("utf-8", stripLineBreaks(value))
+}
+
+// stripLineBreaks заменяет переводы строк пробелами, чтобы данные из события не могли добавить свои заголовки
+func stripLineBreaks(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
 }
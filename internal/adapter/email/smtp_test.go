@@ -0,0 +1,46 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+func TestBuildMIMEMessage(t *testing.T) {
+	sender := &SMTPSender{from: "noreply@avigo.ru", fromName: "АвиGo\r\nBcc: attacker@evil.com"}
+	msg := &domain.EmailMessage{
+		To:       "user@avigo.ru",
+		Subject:  "Новое сообщение от Ивана\r\nBcc: attacker@evil.com",
+		HTMLBody: "<p>Привет</p>",
+	}
+
+	message := sender.buildMIMEMessage(msg)
+
+	headers, body, ok := strings.Cut(message, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("message has no header separator: %q", message)
+	}
+	if body != msg.HTMLBody {
+		t.Errorf("body = %q, want %q", body, msg.HTMLBody)
+	}
+
+	var names []string
+	for _, line := range strings.Split(headers, "\r\n") {
+		name, _, _ := strings.Cut(line, ":")
+		names = append(names, name)
+	}
+	want := []string{"From", "To", "Subject", "MIME-Version", "Content-Type"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("headers = %v, want %v in this order", names, want)
+	}
+
+	if !strings.Contains(headers, "To: <user@avigo.ru>\r\n") {
+		t.Errorf("To header is not a formatted address: %q", headers)
+	}
+
+	// повторная сборка дает то же письмо
+	if again := sender.buildMIMEMessage(msg); again != message {
+		t.Errorf("message differs between builds:\n%q\n%q", message, again)
+	}
+}
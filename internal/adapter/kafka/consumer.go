@@ -3,74 +3,338 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
 )
 
+const (
+	failureMinBackoff = time.Second
+	failureMaxBackoff = 30 * time.Second
+
+	// workerQueueSize - сколько сообщений может ждать своей очереди у одного воркера
+	workerQueueSize = 16
+)
+
+// unknownEventCounter - обработчик, который считает события незарегистрированных типов
+type unknownEventCounter interface {
+	UnknownEvents() map[EventType]uint64
+}
+
 type Consumer struct {
-	reader  *kafka.Reader
-	handler MessageHandler
+	reader   *kafka.Reader
+	handler  MessageHandler
+	failures FailureHandler
+	// delay - минимальная выдержка сообщения перед обработкой, используется retry топиками
+	delay time.Duration
+	// workers - число параллельных обработчиков, сообщения с одним ключом всегда попадают к одному
+	workers int
+	// replay - окно повторной обработки, nil в обычном режиме
+	replay *ReplayWindow
+
+	mu     sync.Mutex
+	status model.TopicStatus
+	// generation - число ребалансировок с запуска, счетчик reader обнуляется при каждом чтении статистики
+	generation int64
 }
 
 type MessageHandler interface {
 	Handle(ctx context.Context, message kafka.Message) error
 }
 
-func NewConsumer(cfg *config.KafkaConfig, handler MessageHandler) *Consumer {
+type FailureHandler interface {
+	HandleFailure(ctx context.Context, msg kafka.Message, cause error) error
+}
+
+// NewConsumers создает по консьюмеру на каждый топик роутера, все в одной consumer group.
+// Отдельный reader на топик позволяет видеть состояние каждого топика независимо
+func NewConsumers(cfg *config.KafkaConfig, router *TopicRouter, failures FailureHandler) ([]*Consumer, error) {
+	security, err := newConnectionSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure kafka connection: %w", err)
+	}
+
+	topics := router.Topics()
+	consumers := make([]*Consumer, 0, len(topics))
+	for _, topic := range topics {
+		consumers = append(consumers, newConsumer(cfg, security, topic, cfg.GroupID, startOffset(cfg), 0, router, failures))
+	}
+	return consumers, nil
+}
+
+// NewRetryConsumers создает по консьюмеру на каждую ступень retry топиков.
+// У каждой ступени своя consumer group, чтобы ребалансировки не задевали основной топик
+func NewRetryConsumers(cfg *config.KafkaConfig, handler MessageHandler, failures FailureHandler) ([]*Consumer, error) {
+	security, err := newConnectionSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure kafka connection: %w", err)
+	}
+
+	consumers := make([]*Consumer, 0, len(cfg.RetryTopics))
+	for _, tier := range cfg.RetryTopics {
+		groupID := fmt.Sprintf("%s.%s", cfg.GroupID, tier.Topic)
+		// в retry топиках только сообщения самого сервиса, новая группа должна забрать их все
+		consumers = append(consumers,
+			newConsumer(cfg, security, tier.Topic, groupID, kafka.FirstOffset, tier.Delay, handler, failures))
+	}
+	return consumers, nil
+}
+
+func newConsumer(
+	cfg *config.KafkaConfig,
+	security *connectionSecurity,
+	topic string,
+	groupID string,
+	startOffset int64,
+	delay time.Duration,
+	handler MessageHandler,
+	failures FailureHandler,
+) *Consumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        cfg.Brokers,
-		Topic:          cfg.TopicUserEvents,
-		GroupID:        cfg.GroupID,
+		Dialer:         security.dialer(),
+		Topic:          topic,
+		GroupID:        groupID,
 		MinBytes:       cfg.MinBytes,
 		MaxBytes:       cfg.MaxBytes,
 		CommitInterval: time.Second,
-		StartOffset:    kafka.LastOffset,
+		StartOffset:    startOffset,
 		Logger:         kafka.LoggerFunc(log.Printf),
 		ErrorLogger:    kafka.LoggerFunc(log.Printf),
 	})
 
 	return &Consumer{
-		reader:  reader,
-		handler: handler,
+		reader:   reader,
+		handler:  handler,
+		failures: failures,
+		delay:    delay,
+		workers:  max(cfg.Workers, 1),
+		status: model.TopicStatus{
+			Topic:   topic,
+			GroupID: groupID,
+		},
 	}
 }
 
+func startOffset(cfg *config.KafkaConfig) int64 {
+	if cfg.StartOffset == config.KafkaStartOffsetFirst {
+		return kafka.FirstOffset
+	}
+	return kafka.LastOffset
+}
+
+// LimitToReplay ограничивает консьюмер окном повторной обработки, вызывается до Start
+func (c *Consumer) LimitToReplay(window *ReplayWindow) {
+	c.replay = window
+}
+
+func (c *Consumer) Topic() string {
+	return c.reader.Config().Topic
+}
+
+// Status возвращает текущее состояние консьюмера
+func (c *Consumer) Status() model.TopicStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := c.status
+	// Lag() у reader с consumer group всегда -1, отставание есть только в статистике
+	status.Lag = c.readStats().Lag
+
+	if counter, ok := c.handler.(unknownEventCounter); ok {
+		for eventType, count := range counter.UnknownEvents() {
+			if status.UnknownEvents == nil {
+				status.UnknownEvents = make(map[string]uint64)
+			}
+			status.UnknownEvents[string(eventType)] = count
+		}
+	}
+	return status
+}
+
+// currentGeneration возвращает число ребалансировок consumer group с запуска консьюмера
+func (c *Consumer) currentGeneration() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readStats()
+	return c.generation
+}
+
+// readStats читает статистику reader и накапливает счетчик ребалансировок, вызывается под c.mu
+func (c *Consumer) readStats() kafka.ReaderStats {
+	stats := c.reader.Stats()
+	c.generation += stats.Rebalances
+	return stats
+}
+
+func (c *Consumer) setRunning(running bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.Running = running
+}
+
+func (c *Consumer) recordResult(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UTC()
+	c.status.LastMessageAt = &now
+	if err == nil {
+		c.status.Processed++
+		return
+	}
+
+	c.status.Failed++
+	c.status.LastError = err.Error()
+	c.status.LastErrorAt = &now
+}
+
 func (c *Consumer) Start(ctx context.Context) error {
-	log.Println("Start Kafka consumer...")
+	log.Printf("Start Kafka consumer for topic %s with %d workers...", c.Topic(), c.workers)
+
+	c.setRunning(true)
+	defer c.setRunning(false)
+
+	tracker := newOffsetTracker()
+	queues := make([]chan trackedMessage, c.workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan trackedMessage, workerQueueSize)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.work(ctx, queues[i], tracker)
+		}()
+	}
+
+	c.fetch(ctx, queues, tracker)
+	if c.replay != nil && c.replay.finished() {
+		log.Printf("Replay of topic %s finished, stopping Kafka consumer...", c.Topic())
+	} else {
+		log.Println("Context cancelled, stopping Kafka consumer...")
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
 
+	return c.Close()
+}
+
+// fetch читает сообщения и раскладывает их по очередям воркеров до отмены контекста
+// или до конца окна повторной обработки
+func (c *Consumer) fetch(ctx context.Context, queues []chan trackedMessage, tracker *offsetTracker) {
 	for {
+		if c.replay != nil && c.replay.finished() {
+			return
+		}
+
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Erro fetching message from Kafka: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		if c.replay != nil && !c.replay.admit(msg) {
+			continue
+		}
+
+		tracked := tracker.track(msg, c.currentGeneration())
+
 		select {
+		case queues[c.workerFor(msg)] <- tracked:
 		case <-ctx.Done():
-			log.Println("Context cancelled, stopping Kafka consumer...")
-			return c.Close()
-		default:
-			msg, err := c.reader.FetchMessage(ctx)
-			if err != nil {
-				if ctx.Err() != nil {
-					return c.Close()
-				}
-				log.Printf("Erro fetching message from Kafka: %v", err)
-				time.Sleep(time.Second)
-				continue
-			}
+			return
+		}
+	}
+}
+
+// workerFor закрепляет ключ сообщения (ID пользователя) за воркером, чтобы события
+// одного пользователя обрабатывались по порядку. Сообщения без ключа упорядочены в пределах партиции
+func (c *Consumer) workerFor(msg kafka.Message) int {
+	if c.workers == 1 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	if len(msg.Key) > 0 {
+		hash.Write(msg.Key)
+	} else {
+		hash.Write([]byte(strconv.Itoa(msg.Partition)))
+	}
+	return int(hash.Sum32() % uint32(c.workers))
+}
+
+func (c *Consumer) work(ctx context.Context, queue <-chan trackedMessage, tracker *offsetTracker) {
+	for tracked := range queue {
+		msg := tracked.Message
+
+		// после отмены контекста только дочитываем очередь: незавершенные сообщения не коммитятся
+		// и будут получены заново
+		if ctx.Err() != nil {
+			continue
+		}
+
+		if err := c.waitDelay(ctx, msg); err != nil {
+			continue
+		}
 
-			if err := c.processMessage(ctx, msg); err != nil {
-				log.Printf("Error processing message: %v", err)
+		err := c.processMessage(ctx, msg)
+		c.recordResult(err)
+		if err != nil {
+			log.Printf("Error processing message: %v", err)
+			// сообщение уходит в retry топик или DLQ, оффсет коммитится,
+			// консьюмер не застревает на одном сообщении
+			if err := c.handleFailure(ctx, msg, err); err != nil {
 				continue
 			}
+		}
 
-			if err := c.reader.CommitMessages(ctx, msg); err != nil {
+		tracker.complete(tracked, func(commit kafka.Message) {
+			if err := c.reader.CommitMessages(ctx, commit); err != nil {
 				log.Printf("Error committing message: %v", err)
 			}
-		}
+		})
+	}
+}
+
+// waitDelay выдерживает сообщение retry топика до истечения задержки ступени.
+// Сообщения в партиции упорядочены по времени публикации, поэтому ожидание первого не задерживает остальные сверх нужного
+func (c *Consumer) waitDelay(ctx context.Context, msg kafka.Message) error {
+	if c.delay <= 0 {
+		return nil
+	}
+
+	wait := time.Until(msg.Time.Add(c.delay))
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
 func (c *Consumer) processMessage(ctx context.Context, msg kafka.Message) error {
-	log.Printf("Processing message from partition %d, offset %d", msg.Partition, msg.Offset)
+	log.Printf("Processing message from %s partition %d, offset %d", msg.Topic, msg.Partition, msg.Offset)
 
 	processCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -82,6 +346,28 @@ func (c *Consumer) processMessage(ctx context.Context, msg kafka.Message) error
 	return nil
 }
 
+// handleFailure передает сообщение в FailureHandler, повторяя попытки до успеха: коммитить оффсет,
+// не сохранив сообщение, нельзя. Ошибка возвращается только при отмене контекста
+func (c *Consumer) handleFailure(ctx context.Context, msg kafka.Message, cause error) error {
+	backoff := failureMinBackoff
+
+	for {
+		err := c.failures.HandleFailure(ctx, msg, cause)
+		if err == nil {
+			return nil
+		}
+
+		log.Printf("Error handling failed message: %v, retrying in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, failureMaxBackoff)
+	}
+}
+
 func (c *Consumer) Close() error {
 	log.Println("Closing Kafka consumer...")
 	if err := c.reader.Close(); err != nil {
@@ -90,3 +376,14 @@ func (c *Consumer) Close() error {
 	log.Println("Kafka consumer closed successfully")
 	return nil
 }
+
+// ConsumerSet - все консьюмеры сервиса, используется для отчета о состоянии по топикам
+type ConsumerSet []*Consumer
+
+func (s ConsumerSet) TopicStatuses() []model.TopicStatus {
+	statuses := make([]model.TopicStatus, 0, len(s))
+	for _, consumer := range s {
+		statuses = append(statuses, consumer.Status())
+	}
+	return statuses
+}
@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+// DeadLetterPublisher складывает сообщения, которые не удалось обработать, в отдельный топик
+// с исходным payload и заголовками, чтобы консьюмер мог идти дальше, а разбор был возможен позже
+type DeadLetterPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewDeadLetterPublisher(cfg *config.KafkaConfig) (*DeadLetterPublisher, error) {
+	security, err := newConnectionSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure kafka connection: %w", err)
+	}
+
+	return &DeadLetterPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(cfg.Brokers...),
+			Topic:                  cfg.DLQTopic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			Transport:              security.transport(),
+		},
+	}, nil
+}
+
+func (p *DeadLetterPublisher) Publish(ctx context.Context, msg kafka.Message, cause error) error {
+	dead := kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: failureHeaders(msg, cause),
+	}
+
+	if err := p.writer.WriteMessages(ctx, dead); err != nil {
+		return fmt.Errorf("failed to publish message to DLQ %s: %w", p.writer.Topic, err)
+	}
+
+	return nil
+}
+
+func (p *DeadLetterPublisher) Close() error {
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close DLQ writer: %w", err)
+	}
+	return nil
+}
@@ -6,74 +6,87 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
-	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/usecase"
 )
 
 type NotificationHandler struct {
-	emailUseCase *usecase.EmailNotificationUseCase
+	registry     *EventRegistry
+	schemas      *SchemaValidator
+	deduplicator *usecase.EventDeduplicator
+	// unknownToDLQ - отправлять события незарегистрированных типов в DLQ вместо пропуска
+	unknownToDLQ bool
 }
 
-func NewNotificationHandler(emailUseCase *usecase.EmailNotificationUseCase) *NotificationHandler {
+func NewNotificationHandler(
+	registry *EventRegistry,
+	schemas *SchemaValidator,
+	deduplicator *usecase.EventDeduplicator,
+	unknownToDLQ bool,
+) *NotificationHandler {
 	return &NotificationHandler{
-		emailUseCase: emailUseCase,
+		registry:     registry,
+		schemas:      schemas,
+		deduplicator: deduplicator,
+		unknownToDLQ: unknownToDLQ,
 	}
 }
 
 func (h *NotificationHandler) Handle(ctx context.Context, message kafka.Message) error {
 	log.Printf("Received message: key=%s, value=%s", string(message.Key), string(message.Value))
 
-	var baseEvent UserEvent
+	var baseEvent Envelope
 	if err := json.Unmarshal(message.Value, &baseEvent); err != nil {
 		return fmt.Errorf("failed to unmarshal base event: %w", err)
 	}
 
-	switch baseEvent.EventType {
-	case EventTypeEmailVerification:
-		return h.handleEmailVerification(ctx, message.Value)
-	default:
-		log.Printf("Unknown event type: %s", baseEvent.EventType)
-		return nil
+	if err := baseEvent.Validate(); err != nil {
+		return fmt.Errorf("invalid event: %w", err)
 	}
-}
-
-func (h *NotificationHandler) handleEmailVerification(ctx context.Context, data []byte) error {
 
-	var event EmailVerificationEvent
-	if err := json.Unmarshal(data, &event); err != nil {
-		return fmt.Errorf("failed to unmarshal email verification event: %w", err)
+	route, ok := h.registry.route(baseEvent.EventType)
+	if !ok {
+		return h.handleUnknown(baseEvent)
 	}
 
-	// if err := event.Validate(); err != nil {
-	//     return fmt.Errorf("event validation failed: %w", err)
-	// }
+	if err := h.schemas.Validate(baseEvent.EventType, baseEvent.SchemaVersion, message.Value); err != nil {
+		return fmt.Errorf("invalid event: %w", err)
+	}
 
-	userID, err := uuid.Parse(event.UserID)
+	processed, err := h.deduplicator.IsProcessed(ctx, baseEvent.EventID)
 	if err != nil {
-		return fmt.Errorf("invalid user ID: %w", err)
+		return err
 	}
-
-	req := model.SendEmailNotificationRequest{
-		UserID:           userID,
-		Email:            event.Email,
-		DisplayName:      event.DisplayName,
-		ConfirmationCode: event.ConfirmationCode,
-		ExpiresAt:        event.ExpiresAt,
+	if processed {
+		log.Printf("Event %s already processed, skipping", baseEvent.EventID)
+		return nil
 	}
 
-	resp, err := h.emailUseCase.SendRegistrationEmail(ctx, req)
+	event, err := route.decode(message.Value)
 	if err != nil {
-		return fmt.Errorf("failed to send registration email: %w", err)
+		return fmt.Errorf("failed to unmarshal %s event: %w", baseEvent.EventType, err)
 	}
 
-	if !resp.IsSuccess() {
-		return fmt.Errorf("email sending failed: %w", resp.Error)
+	if err := route.validate(event); err != nil {
+		return fmt.Errorf("invalid %s event: %w", baseEvent.EventType, err)
 	}
 
-	log.Printf("Successfully sent registration email to %s (notification_id: %s)",
-		req.Email, resp.NotificationID)
+	return route.process(ctx, event)
+}
+
+// UnknownEvents возвращает число полученных событий незарегистрированных типов
+func (h *NotificationHandler) UnknownEvents() map[EventType]uint64 {
+	return h.registry.UnknownEvents()
+}
+
+func (h *NotificationHandler) handleUnknown(event Envelope) error {
+	count := h.registry.countUnknown(event.EventType)
+
+	if h.unknownToDLQ {
+		return fmt.Errorf("%w: %s", ErrUnknownEventType, event.EventType)
+	}
 
+	log.Printf("Unknown event type: %s (event_id: %s, seen %d times), skipping",
+		event.EventType, event.EventID, count)
 	return nil
 }
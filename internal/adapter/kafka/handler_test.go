@@ -0,0 +1,31 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestNotificationHandlerCountsUnknownEvents(t *testing.T) {
+	message := kafka.Message{Value: []byte(`{"event_id":"evt-1","event_type":"user.deleted","schema_version":1}`)}
+
+	skipping := NewNotificationHandler(NewEventRegistry(), nil, nil, false)
+	for range 2 {
+		if err := skipping.Handle(context.Background(), message); err != nil {
+			t.Fatalf("Handle() error = %v, want unknown event skipped", err)
+		}
+	}
+	if got := skipping.UnknownEvents()["user.deleted"]; got != 2 {
+		t.Errorf("UnknownEvents()[user.deleted] = %d, want 2", got)
+	}
+
+	toDLQ := NewNotificationHandler(NewEventRegistry(), nil, nil, true)
+	if err := toDLQ.Handle(context.Background(), message); !errors.Is(err, ErrUnknownEventType) {
+		t.Errorf("Handle() error = %v, want ErrUnknownEventType", err)
+	}
+	if got := toDLQ.UnknownEvents()["user.deleted"]; got != 1 {
+		t.Errorf("UnknownEvents()[user.deleted] = %d, want 1", got)
+	}
+}
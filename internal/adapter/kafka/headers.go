@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Заголовки, которыми помечается сообщение при отправке в retry топик или DLQ
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderFailureCount      = "x-failure-count"
+	HeaderFailedAt          = "x-failed-at"
+)
+
+// failureHeaders копирует заголовки исходного сообщения и добавляет к ним информацию об ошибке.
+// Координаты x-original-* выставляются только при первой неудаче, чтобы после прохода
+// по retry топикам они по-прежнему указывали на исходный топик
+func failureHeaders(msg kafka.Message, cause error) []kafka.Header {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+6)
+	original := false
+
+	for _, h := range msg.Headers {
+		switch h.Key {
+		case HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset:
+			original = true
+			headers = append(headers, h)
+		case HeaderError, HeaderFailureCount, HeaderFailedAt:
+			// пересобираем заново
+		default:
+			headers = append(headers, h)
+		}
+	}
+
+	if !original {
+		headers = append(headers,
+			kafka.Header{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+			kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+			kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		)
+	}
+
+	return append(headers,
+		kafka.Header{Key: HeaderError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderFailureCount, Value: []byte(strconv.Itoa(failureCount(msg) + 1))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+}
+
+// failureCount - сколько раз сообщение уже не удалось обработать до текущей попытки
+func failureCount(msg kafka.Message) int {
+	value, ok := headerValue(msg, HeaderFailureCount)
+	if !ok {
+		return 0
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+func headerValue(msg kafka.Message, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
@@ -1,21 +1,70 @@
 package kafka
 
-import "time"
+import (
+	"time"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
 
 type EventType string
 
 const (
 	EventTypeEmailVerification EventType = "user.email.verification.requested"
-	// реализуем в будущем, по сути)
-	EventTypeChatMessage    EventType = "user.notification.chat.message"
-	EventTypeListingUpdate  EventType = "user.notification.listing.update"
-	EventTypeReviewReceived EventType = "user.notification.review.received"
+	EventTypeChatMessage       EventType = "user.notification.chat.message"
+	EventTypeListingUpdate     EventType = "user.notification.listing.update"
+	EventTypeReviewReceived    EventType = "user.notification.review.received"
+
+	// события топика заказов
+	EventTypeOrderCreated       EventType = "order.created"
+	EventTypeOrderStatusChanged EventType = "order.status.changed"
+
+	// отмена отложенных уведомлений, принимается из любого топика
+	EventTypeNotificationCancel EventType = "notification.cancel.requested"
 )
 
+// Envelope - общий заголовок всех событий. Поля события лежат на том же уровне,
+// вся структура сообщения описывается JSON Schema для пары event_type и schema_version
+type Envelope struct {
+	// EventID - уникальный ID события от продюсера, по нему повторные доставки отбрасываются
+	EventID       string    `json:"event_id"`
+	EventType     EventType `json:"event_type"`
+	SchemaVersion int       `json:"schema_version"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Producer      string    `json:"producer"`
+	// SendAt - необязательное время отложенной доставки уведомления
+	SendAt *time.Time `json:"send_at,omitempty"`
+	// Timestamp - время события в старом формате, до перехода продюсеров на occurred_at и producer
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// legacySchemaVersion - версия событий, отправленных до появления schema_version
+const legacySchemaVersion = 1
+
+// Validate проверяет заголовок и приводит события старого формата к текущему:
+// без schema_version событие считается v1, timestamp заменяет occurred_at
+func (e *Envelope) Validate() error {
+	if e.EventID == "" {
+		return domain.ErrMissingEventID
+	}
+	if e.EventType == "" {
+		return domain.ErrMissingEventType
+	}
+	if e.SchemaVersion == 0 {
+		e.SchemaVersion = legacySchemaVersion
+	}
+	if e.SchemaVersion < 1 {
+		return domain.ErrMissingSchemaVersion
+	}
+	if e.OccurredAt.IsZero() && e.Timestamp != nil {
+		e.OccurredAt = *e.Timestamp
+	}
+	return nil
+}
+
+// UserEvent - событие, адресованное одному пользователю
 type UserEvent struct {
-	EventType EventType `json:"event_type"`
-	UserID    string    `json:"user_id"`
-	Timestamp time.Time `json:"timestamp"`
+	Envelope
+	UserID string `json:"user_id"`
 }
 
 type EmailVerificationEvent struct {
@@ -25,3 +74,137 @@ type EmailVerificationEvent struct {
 	ConfirmationCode string    `json:"confirmation_code"`
 	ExpiresAt        time.Time `json:"expires_at"`
 }
+
+func (e *EmailVerificationEvent) Validate() error {
+	if e.UserID == "" {
+		return domain.ErrMissingUserID
+	}
+	if e.Email == "" {
+		return domain.ErrMissingEmail
+	}
+	if e.ConfirmationCode == "" {
+		return domain.ErrMissingConfirmationCode
+	}
+	return nil
+}
+
+type ChatMessageEvent struct {
+	UserEvent              // UserID - получатель сообщения
+	ChatID          string `json:"chat_id"`
+	MessageID       string `json:"message_id"`
+	SenderID        string `json:"sender_id"`
+	SenderName      string `json:"sender_name"`
+	Text            string `json:"text"`
+	RecipientEmail  string `json:"recipient_email"`
+	RecipientOnline bool   `json:"recipient_online"`
+}
+
+func (e *ChatMessageEvent) Validate() error {
+	if e.UserID == "" {
+		return domain.ErrMissingUserID
+	}
+	if e.ChatID == "" {
+		return domain.ErrMissingChatID
+	}
+	if e.SenderID == "" {
+		return domain.ErrMissingSenderID
+	}
+	return nil
+}
+
+type ListingUpdateEvent struct {
+	UserEvent              // UserID - получатель: владелец объявления или подписчик на изменения
+	ListingID      string  `json:"listing_id"`
+	ListingTitle   string  `json:"listing_title"`
+	ChangeKind     string  `json:"change_kind"` // price_changed | status_changed | moderation_rejected
+	OldPrice       float64 `json:"old_price,omitempty"`
+	NewPrice       float64 `json:"new_price,omitempty"`
+	Currency       string  `json:"currency,omitempty"`
+	NewStatus      string  `json:"new_status,omitempty"` // sold | archived | moderated
+	Reason         string  `json:"reason,omitempty"`
+	RecipientEmail string  `json:"recipient_email,omitempty"`
+}
+
+func (e *ListingUpdateEvent) Validate() error {
+	if e.UserID == "" {
+		return domain.ErrMissingUserID
+	}
+	if e.ListingID == "" {
+		return domain.ErrMissingListingID
+	}
+	return nil
+}
+
+type ReviewReceivedEvent struct {
+	UserEvent                             // UserID - продавец, которому оставили отзыв
+	ReviewID       string                 `json:"review_id"`
+	ListingID      string                 `json:"listing_id"`
+	ListingTitle   string                 `json:"listing_title"`
+	ReviewerID     string                 `json:"reviewer_id"`
+	ReviewerName   string                 `json:"reviewer_name"`
+	Rating         int                    `json:"rating"`
+	Text           string                 `json:"text"`
+	RecipientEmail string                 `json:"recipient_email,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (e *ReviewReceivedEvent) Validate() error {
+	if e.UserID == "" {
+		return domain.ErrMissingUserID
+	}
+	if e.ReviewID == "" {
+		return domain.ErrMissingReviewID
+	}
+	return nil
+}
+
+// OrderEvent - событие сервиса заказов, получатели - обе стороны заказа
+type OrderEvent struct {
+	Envelope
+	OrderID        string  `json:"order_id"`
+	ListingID      string  `json:"listing_id"`
+	ListingTitle   string  `json:"listing_title"`
+	BuyerID        string  `json:"buyer_id"`
+	BuyerName      string  `json:"buyer_name"`
+	BuyerEmail     string  `json:"buyer_email,omitempty"`
+	SellerID       string  `json:"seller_id"`
+	SellerName     string  `json:"seller_name"`
+	SellerEmail    string  `json:"seller_email,omitempty"`
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+	Status         string  `json:"status,omitempty"` // paid | shipped | delivered | cancelled | disputed
+	TrackingNumber string  `json:"tracking_number,omitempty"`
+	Reason         string  `json:"reason,omitempty"`
+}
+
+func (e *OrderEvent) Validate() error {
+	if e.OrderID == "" {
+		return domain.ErrMissingOrderID
+	}
+	if e.BuyerID == "" {
+		return domain.ErrMissingBuyerID
+	}
+	if e.SellerID == "" {
+		return domain.ErrMissingSellerID
+	}
+	if e.BuyerID == e.SellerID {
+		return domain.ErrBuyerIsSeller
+	}
+	return nil
+}
+
+// NotificationCancelEvent отменяет еще не выпущенные отложенные уведомления,
+// созданные из события TargetEventID
+type NotificationCancelEvent struct {
+	Envelope
+	TargetEventID string `json:"target_event_id"`
+	// UserID необязателен: без него отменяются уведомления всех получателей события
+	UserID string `json:"user_id,omitempty"`
+}
+
+func (e *NotificationCancelEvent) Validate() error {
+	if e.TargetEventID == "" {
+		return domain.ErrMissingTargetEventID
+	}
+	return nil
+}
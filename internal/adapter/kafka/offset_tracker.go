@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker следит за сообщениями, которые обрабатываются параллельно.
+// Оффсет партиции можно закоммитить, только когда обработаны все более ранние сообщения этой партиции,
+// иначе после перезапуска незавершенные сообщения будут потеряны
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+	epochs     int
+}
+
+type partitionOffsets struct {
+	epoch      int   // номер назначения партиции этому консьюмеру
+	generation int64 // поколение consumer group, в котором партиция назначена
+	pending    []int64
+	done       map[int64]bool
+}
+
+// trackedMessage - сообщение вместе с назначением партиции, в котором оно получено.
+// Сообщения прежних назначений после ребалансировки уже ничего не коммитят
+type trackedMessage struct {
+	kafka.Message
+	epoch int
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{
+		partitions: make(map[int]*partitionOffsets),
+	}
+}
+
+// track регистрирует полученное сообщение до передачи его воркеру. generation растет с каждой
+// ребалансировкой consumer group
+func (t *offsetTracker) track(msg kafka.Message, generation int64) trackedMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	partition, ok := t.partitions[msg.Partition]
+	// после ребалансировки партиция читается с закоммиченного оффсета - назад, если она вернулась
+	// до коммита, или вперед, если ее успел продвинуть другой консьюмер. Прежнее состояние
+	// к ней больше не относится
+	if !ok || partition.generation != generation ||
+		(len(partition.pending) > 0 && msg.Offset <= partition.pending[len(partition.pending)-1]) {
+		t.epochs++
+		partition = &partitionOffsets{
+			epoch:      t.epochs,
+			generation: generation,
+			done:       make(map[int64]bool),
+		}
+		t.partitions[msg.Partition] = partition
+	}
+
+	partition.pending = append(partition.pending, msg.Offset)
+	return trackedMessage{Message: msg, epoch: partition.epoch}
+}
+
+// complete отмечает сообщение обработанным и, если сдвинулась граница непрерывно обработанных
+// сообщений партиции, вызывает commit для последнего из них. commit вызывается под блокировкой,
+// поэтому коммиты одной партиции не обгоняют друг друга
+func (t *offsetTracker) complete(msg trackedMessage, commit func(kafka.Message)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	partition, ok := t.partitions[msg.Partition]
+	if !ok || partition.epoch != msg.epoch {
+		return
+	}
+	partition.done[msg.Offset] = true
+
+	committable := int64(-1)
+	for len(partition.pending) > 0 && partition.done[partition.pending[0]] {
+		committable = partition.pending[0]
+		delete(partition.done, committable)
+		partition.pending = partition.pending[1:]
+	}
+
+	if committable >= 0 {
+		commit(kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: committable})
+	}
+}
@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestOffsetTracker(t *testing.T) {
+	type step struct {
+		fetch      int64 // оффсет полученного сообщения, -1 - шаг завершения
+		generation int64
+		complete   int // индекс ранее полученного сообщения
+	}
+	fetch := func(offset, generation int64) step { return step{fetch: offset, generation: generation} }
+	complete := func(i int) step { return step{fetch: -1, complete: i} }
+
+	tests := []struct {
+		name    string
+		steps   []step
+		commits []int64
+	}{
+		{
+			name:    "in order completion commits every message",
+			steps:   []step{fetch(10, 1), fetch(11, 1), complete(0), complete(1)},
+			commits: []int64{10, 11},
+		},
+		{
+			name:    "out of order completion waits for earlier messages",
+			steps:   []step{fetch(10, 1), fetch(11, 1), fetch(12, 1), complete(2), complete(1), complete(0)},
+			commits: []int64{12},
+		},
+		{
+			name:    "gap in the middle holds back later offsets",
+			steps:   []step{fetch(10, 1), fetch(11, 1), fetch(12, 1), complete(0), complete(2), complete(1)},
+			commits: []int64{10, 12},
+		},
+		{
+			name: "partition returns at a lower offset",
+			steps: []step{
+				fetch(10, 1), fetch(11, 1),
+				fetch(10, 1), // ребалансировка без коммита: партиция читается заново
+				complete(0),  // сообщение прежнего назначения ничего не коммитит
+				complete(2),
+			},
+			commits: []int64{10},
+		},
+		{
+			name: "partition returns at a higher offset after rebalance",
+			steps: []step{
+				fetch(10, 1), fetch(11, 1),
+				fetch(50, 2), // другой консьюмер успел продвинуть партицию
+				complete(2),
+				complete(0), complete(1), // прежние сообщения не откатывают оффсет
+			},
+			commits: []int64{50},
+		},
+		{
+			name: "stale completion does not release the same offset of new assignment",
+			steps: []step{
+				fetch(10, 1),
+				fetch(10, 2), fetch(11, 2),
+				complete(0), // прежняя копия оффсета 10
+				complete(2),
+				complete(1),
+			},
+			commits: []int64{11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newOffsetTracker()
+			var (
+				fetched []trackedMessage
+				commits []int64
+			)
+
+			for _, s := range tt.steps {
+				if s.fetch >= 0 {
+					msg := kafka.Message{Topic: "events", Partition: 0, Offset: s.fetch}
+					fetched = append(fetched, tracker.track(msg, s.generation))
+					continue
+				}
+				tracker.complete(fetched[s.complete], func(commit kafka.Message) {
+					commits = append(commits, commit.Offset)
+				})
+			}
+
+			if !slices.Equal(commits, tt.commits) {
+				t.Errorf("commits = %v, want %v", commits, tt.commits)
+			}
+		})
+	}
+}
+
+func TestOffsetTrackerPartitionsAreIndependent(t *testing.T) {
+	tracker := newOffsetTracker()
+	first := tracker.track(kafka.Message{Partition: 0, Offset: 5}, 1)
+	second := tracker.track(kafka.Message{Partition: 1, Offset: 7}, 1)
+
+	var commits []kafka.Message
+	record := func(commit kafka.Message) { commits = append(commits, commit) }
+
+	tracker.complete(second, record)
+	tracker.complete(first, record)
+
+	if len(commits) != 2 || commits[0].Partition != 1 || commits[0].Offset != 7 ||
+		commits[1].Partition != 0 || commits[1].Offset != 5 {
+		t.Errorf("commits = %+v, want partition 1 offset 7 then partition 0 offset 5", commits)
+	}
+}
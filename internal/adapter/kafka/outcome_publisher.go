@@ -0,0 +1,94 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+const (
+	outcomeSchemaVersion = 1
+	serviceProducer      = "notification-service"
+)
+
+// NotificationOutcomeEvent - событие об итоге доставки уведомления для других сервисов
+type NotificationOutcomeEvent struct {
+	Envelope
+	NotificationID   string `json:"notification_id"`
+	UserID           string `json:"user_id"`
+	NotificationType string `json:"notification_type"`
+	SourceEventID    string `json:"source_event_id,omitempty"`
+	Channel          string `json:"channel,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+// OutcomePublisher публикует notification.sent, notification.failed и notification.read.
+// Ключ сообщения - ID пользователя, чтобы события одного пользователя шли по порядку
+type OutcomePublisher struct {
+	writer *kafka.Writer
+}
+
+func NewOutcomePublisher(cfg *config.KafkaConfig) (*OutcomePublisher, error) {
+	security, err := newConnectionSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure kafka connection: %w", err)
+	}
+
+	return &OutcomePublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(cfg.Brokers...),
+			Topic:                  cfg.OutcomeTopic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			// публикация синхронная, ждать заполнения пачки по умолчанию (1s) незачем
+			BatchTimeout: 10 * time.Millisecond,
+			Transport:    security.transport(),
+		},
+	}, nil
+}
+
+func (p *OutcomePublisher) PublishOutcome(ctx context.Context, outcome domain.DeliveryOutcome) error {
+	event := NotificationOutcomeEvent{
+		Envelope: Envelope{
+			EventID:       outcome.ID.String(),
+			EventType:     EventType(outcome.Type),
+			SchemaVersion: outcomeSchemaVersion,
+			OccurredAt:    outcome.OccurredAt,
+			Producer:      serviceProducer,
+		},
+		NotificationID:   outcome.NotificationID.String(),
+		UserID:           outcome.UserID.String(),
+		NotificationType: string(outcome.NotificationType),
+		SourceEventID:    outcome.SourceEventID,
+		Channel:          string(outcome.Channel),
+		Reason:           outcome.Reason,
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", outcome.Type, err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: value,
+	}
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish %s event to %s: %w", outcome.Type, p.writer.Topic, err)
+	}
+
+	return nil
+}
+
+func (p *OutcomePublisher) Close() error {
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close outcome writer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/usecase"
+)
+
+// Обработчики событий для EventRegistry: каждый переводит событие в запрос use case.
+// Повтор уже обработанного события (domain.ErrDuplicateEvent) - штатная ситуация, а не ошибка
+
+func RegisterEmailVerification(registry *EventRegistry, uc *usecase.EmailNotificationUseCase) {
+	Register(registry, EventTypeEmailVerification, (*EmailVerificationEvent).Validate,
+		func(ctx context.Context, event *EmailVerificationEvent) error {
+			userID, err := uuid.Parse(event.UserID)
+			if err != nil {
+				return fmt.Errorf("invalid user ID: %w", err)
+			}
+
+			req := model.SendEmailNotificationRequest{
+				EventID:          event.EventID,
+				UserID:           userID,
+				Email:            event.Email,
+				DisplayName:      event.DisplayName,
+				ConfirmationCode: event.ConfirmationCode,
+				ExpiresAt:        event.ExpiresAt,
+			}
+
+			resp, err := uc.SendRegistrationEmail(ctx, req)
+			if errors.Is(err, domain.ErrDuplicateEvent) {
+				// параллельная или повторная доставка того же события уже создала уведомление
+				log.Printf("Event %s already processed, skipping", event.EventID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to send registration email: %w", err)
+			}
+
+			if !resp.IsSuccess() {
+				return fmt.Errorf("email sending failed: %w", resp.Error)
+			}
+
+			log.Printf("Registration email to %s queued for delivery (notification_id: %s)",
+				req.Email, resp.NotificationID)
+
+			return nil
+		},
+	)
+}
+
+func RegisterChatMessage(registry *EventRegistry, uc *usecase.ChatNotificationUseCase) {
+	Register(registry, EventTypeChatMessage, (*ChatMessageEvent).Validate,
+		func(ctx context.Context, event *ChatMessageEvent) error {
+			userID, err := uuid.Parse(event.UserID)
+			if err != nil {
+				return fmt.Errorf("invalid user ID: %w", err)
+			}
+
+			senderID, err := uuid.Parse(event.SenderID)
+			if err != nil {
+				return fmt.Errorf("invalid sender ID: %w", err)
+			}
+
+			req := model.ChatMessageNotificationRequest{
+				EventID:         event.EventID,
+				UserID:          userID,
+				ChatID:          event.ChatID,
+				MessageID:       event.MessageID,
+				SenderID:        senderID,
+				SenderName:      event.SenderName,
+				Text:            event.Text,
+				RecipientEmail:  event.RecipientEmail,
+				RecipientOnline: event.RecipientOnline,
+				SendAt:          event.SendAt,
+			}
+
+			notification, err := uc.NotifyNewMessage(ctx, req)
+			if errors.Is(err, domain.ErrDuplicateEvent) {
+				log.Printf("Event %s already processed, skipping", event.EventID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to notify about chat message: %w", err)
+			}
+
+			log.Printf("Chat message notification created for user %s (notification_id: %s)",
+				userID, notification.Id)
+
+			return nil
+		},
+	)
+}
+
+func RegisterListingUpdate(registry *EventRegistry, uc *usecase.ListingNotificationUseCase) {
+	Register(registry, EventTypeListingUpdate, (*ListingUpdateEvent).Validate,
+		func(ctx context.Context, event *ListingUpdateEvent) error {
+			userID, err := uuid.Parse(event.UserID)
+			if err != nil {
+				return fmt.Errorf("invalid user ID: %w", err)
+			}
+
+			req := model.ListingUpdateNotificationRequest{
+				EventID:        event.EventID,
+				UserID:         userID,
+				ListingID:      event.ListingID,
+				ListingTitle:   event.ListingTitle,
+				ChangeKind:     domain.ListingChangeKind(event.ChangeKind),
+				OldPrice:       event.OldPrice,
+				NewPrice:       event.NewPrice,
+				Currency:       event.Currency,
+				NewStatus:      domain.ListingStatus(event.NewStatus),
+				Reason:         event.Reason,
+				RecipientEmail: event.RecipientEmail,
+				SendAt:         event.SendAt,
+			}
+
+			notification, err := uc.NotifyListingUpdate(ctx, req)
+			if errors.Is(err, domain.ErrDuplicateEvent) {
+				log.Printf("Event %s already processed, skipping", event.EventID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to notify about listing update: %w", err)
+			}
+
+			log.Printf("Listing %s update (%s) notification created for user %s (notification_id: %s)",
+				event.ListingID, event.ChangeKind, userID, notification.Id)
+
+			return nil
+		},
+	)
+}
+
+func RegisterReviewReceived(registry *EventRegistry, uc *usecase.ReviewNotificationUseCase) {
+	Register(registry, EventTypeReviewReceived, (*ReviewReceivedEvent).Validate,
+		func(ctx context.Context, event *ReviewReceivedEvent) error {
+			userID, err := uuid.Parse(event.UserID)
+			if err != nil {
+				return fmt.Errorf("invalid user ID: %w", err)
+			}
+
+			// reviewer_id необязателен: отзыв может быть анонимным
+			var reviewerID uuid.UUID
+			if event.ReviewerID != "" {
+				reviewerID, err = uuid.Parse(event.ReviewerID)
+				if err != nil {
+					return fmt.Errorf("invalid reviewer ID: %w", err)
+				}
+			}
+
+			req := model.ReviewReceivedNotificationRequest{
+				EventID:        event.EventID,
+				UserID:         userID,
+				ReviewID:       event.ReviewID,
+				ListingID:      event.ListingID,
+				ListingTitle:   event.ListingTitle,
+				ReviewerID:     reviewerID,
+				ReviewerName:   event.ReviewerName,
+				Rating:         event.Rating,
+				Text:           event.Text,
+				RecipientEmail: event.RecipientEmail,
+				Metadata:       event.Metadata,
+				SendAt:         event.SendAt,
+			}
+
+			notification, err := uc.NotifyReviewReceived(ctx, req)
+			if errors.Is(err, domain.ErrDuplicateEvent) {
+				log.Printf("Event %s already processed, skipping", event.EventID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to notify about review: %w", err)
+			}
+
+			log.Printf("Review %s notification created for seller %s (notification_id: %s)",
+				event.ReviewID, userID, notification.Id)
+
+			return nil
+		},
+	)
+}
+
+func RegisterOrderEvents(registry *EventRegistry, uc *usecase.OrderNotificationUseCase) {
+	process := func(ctx context.Context, event *OrderEvent) error {
+		return processOrderEvent(ctx, uc, event)
+	}
+	Register(registry, EventTypeOrderCreated, (*OrderEvent).Validate, process)
+	Register(registry, EventTypeOrderStatusChanged, (*OrderEvent).Validate, process)
+}
+
+func processOrderEvent(ctx context.Context, uc *usecase.OrderNotificationUseCase, event *OrderEvent) error {
+	buyerID, err := uuid.Parse(event.BuyerID)
+	if err != nil {
+		return fmt.Errorf("invalid buyer ID: %w", err)
+	}
+	sellerID, err := uuid.Parse(event.SellerID)
+	if err != nil {
+		return fmt.Errorf("invalid seller ID: %w", err)
+	}
+
+	req := model.OrderNotificationRequest{
+		EventID:      event.EventID,
+		OrderID:      event.OrderID,
+		ListingID:    event.ListingID,
+		ListingTitle: event.ListingTitle,
+		Buyer: model.OrderParticipant{
+			UserID: buyerID,
+			Name:   event.BuyerName,
+			Email:  event.BuyerEmail,
+		},
+		Seller: model.OrderParticipant{
+			UserID: sellerID,
+			Name:   event.SellerName,
+			Email:  event.SellerEmail,
+		},
+		Amount:         event.Amount,
+		Currency:       event.Currency,
+		Status:         domain.OrderStatus(event.Status),
+		TrackingNumber: event.TrackingNumber,
+		Reason:         event.Reason,
+		SendAt:         event.SendAt,
+	}
+
+	var notifications []*domain.Notification
+	if event.EventType == EventTypeOrderCreated {
+		// статус в order.created не несет смысла, шаблон выбирается по типу события
+		req.Status = ""
+		notifications, err = uc.NotifyOrderCreated(ctx, req)
+	} else {
+		notifications, err = uc.NotifyOrderStatusChanged(ctx, req)
+	}
+	if errors.Is(err, domain.ErrDuplicateEvent) {
+		log.Printf("Event %s already processed, skipping", event.EventID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to notify about order %s: %w", event.OrderID, err)
+	}
+
+	log.Printf("Order %s (%s) notifications created for buyer %s and seller %s (notification_ids: %s, %s)",
+		event.OrderID, event.EventType, buyerID, sellerID, notifications[0].Id, notifications[1].Id)
+
+	return nil
+}
+
+func RegisterNotificationCancel(registry *EventRegistry, scheduler *usecase.NotificationScheduler) {
+	Register(registry, EventTypeNotificationCancel, (*NotificationCancelEvent).Validate,
+		func(ctx context.Context, event *NotificationCancelEvent) error {
+			var userID uuid.UUID
+			if event.UserID != "" {
+				var err error
+				userID, err = uuid.Parse(event.UserID)
+				if err != nil {
+					return fmt.Errorf("invalid user ID: %w", err)
+				}
+			}
+
+			req := model.CancelScheduledRequest{
+				EventID:       event.EventID,
+				TargetEventID: event.TargetEventID,
+				UserID:        userID,
+			}
+
+			cancelled, err := scheduler.CancelScheduled(ctx, req)
+			if err != nil {
+				return fmt.Errorf("failed to cancel scheduled notifications: %w", err)
+			}
+
+			log.Printf("Cancelled %d scheduled notifications of event %s", cancelled, event.TargetEventID)
+
+			return nil
+		},
+	)
+}
@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// eventRoute - обработка одного типа события: декодирование, проверка и бизнес-логика
+type eventRoute struct {
+	decode   func(data []byte) (any, error)
+	validate func(event any) error
+	process  func(ctx context.Context, event any) error
+}
+
+// EventRegistry сопоставляет тип события с его обработкой.
+// Новый вид события подключается регистрацией, без изменений в NotificationHandler и Consumer
+type EventRegistry struct {
+	routes map[EventType]eventRoute
+
+	mu      sync.Mutex
+	unknown map[EventType]uint64
+}
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{
+		routes:  make(map[EventType]eventRoute),
+		unknown: make(map[EventType]uint64),
+	}
+}
+
+// Register подключает обработку событий типа eventType, полезная нагрузка которых декодируется в E.
+// validate может быть nil. Повторная регистрация типа - ошибка программиста, поэтому паника
+func Register[E any](
+	r *EventRegistry,
+	eventType EventType,
+	validate func(event *E) error,
+	process func(ctx context.Context, event *E) error,
+) {
+	if _, exists := r.routes[eventType]; exists {
+		panic(fmt.Sprintf("kafka: event type %s is already registered", eventType))
+	}
+
+	route := eventRoute{
+		decode: func(data []byte) (any, error) {
+			event := new(E)
+			if err := json.Unmarshal(data, event); err != nil {
+				return nil, err
+			}
+			return event, nil
+		},
+		validate: func(event any) error { return nil },
+		process: func(ctx context.Context, event any) error {
+			return process(ctx, event.(*E))
+		},
+	}
+	if validate != nil {
+		route.validate = func(event any) error {
+			return validate(event.(*E))
+		}
+	}
+
+	r.routes[eventType] = route
+}
+
+// Types возвращает зарегистрированные типы событий
+func (r *EventRegistry) Types() []EventType {
+	types := make([]EventType, 0, len(r.routes))
+	for eventType := range r.routes {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+func (r *EventRegistry) route(eventType EventType) (eventRoute, bool) {
+	route, ok := r.routes[eventType]
+	return route, ok
+}
+
+// countUnknown учитывает событие незарегистрированного типа и возвращает, сколько таких уже было
+func (r *EventRegistry) countUnknown(eventType EventType) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unknown[eventType]++
+	return r.unknown[eventType]
+}
+
+// UnknownEvents возвращает число полученных событий незарегистрированных типов
+func (r *EventRegistry) UnknownEvents() map[EventType]uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[EventType]uint64, len(r.unknown))
+	for eventType, count := range r.unknown {
+		counts[eventType] = count
+	}
+	return counts
+}
@@ -0,0 +1,240 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+// ResetGroupForReplay сдвигает оффсеты consumer group на начало окна из cfg.Replay.
+// Если у окна есть верхняя граница, возвращается ReplayWindow для консьюмера топика, иначе nil.
+// Коммит без членства в группе принимается брокером, только пока в группе нет активных участников,
+// поэтому остальные реплики должны быть остановлены
+func ResetGroupForReplay(ctx context.Context, cfg *config.KafkaConfig) (*ReplayWindow, error) {
+	security, err := newConnectionSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure kafka connection: %w", err)
+	}
+
+	client := &kafka.Client{
+		Addr:      kafka.TCP(cfg.Brokers...),
+		Timeout:   30 * time.Second,
+		Transport: security.transport(),
+	}
+	replay := cfg.Replay
+
+	partitions, err := topicPartitions(ctx, client, replay.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts map[int]int64
+	bounds := make(map[int]int64)
+	if len(replay.Offsets) > 0 {
+		starts = make(map[int]int64, len(replay.Offsets))
+		for partition, offsets := range replay.Offsets {
+			if !containsPartition(partitions, partition) {
+				return nil, fmt.Errorf("topic %s has no partition %d", replay.Topic, partition)
+			}
+			starts[partition] = offsets.Start
+			if offsets.End >= 0 {
+				bounds[partition] = offsets.End
+			}
+		}
+	} else {
+		starts, err = offsetsAtTime(ctx, client, replay.Topic, partitions, replay.FromTime)
+		if err != nil {
+			return nil, err
+		}
+
+		if !replay.UntilTime.IsZero() {
+			ends, err := offsetsAtTime(ctx, client, replay.Topic, partitions, replay.UntilTime)
+			if err != nil {
+				return nil, err
+			}
+			// граница по времени указывает на первое сообщение после окна
+			for partition, end := range ends {
+				bounds[partition] = end - 1
+			}
+		}
+	}
+
+	commits := make([]kafka.OffsetCommit, 0, len(starts))
+	for partition, offset := range starts {
+		commits = append(commits, kafka.OffsetCommit{Partition: partition, Offset: offset})
+	}
+
+	resp, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      cfg.GroupID,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{replay.Topic: commits},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset offsets of group %s: %w", cfg.GroupID, err)
+	}
+	for _, partition := range resp.Topics[replay.Topic] {
+		if partition.Error != nil {
+			return nil, fmt.Errorf("failed to reset offset of %s partition %d (is the group still active?): %w",
+				replay.Topic, partition.Partition, partition.Error)
+		}
+	}
+
+	for partition, offset := range starts {
+		log.Printf("Replay: group %s reset to offset %d on %s partition %d", cfg.GroupID, offset, replay.Topic, partition)
+	}
+
+	if len(bounds) == 0 {
+		return nil, nil
+	}
+	return newReplayWindow(replay.Topic, starts, bounds), nil
+}
+
+func topicPartitions(ctx context.Context, client *kafka.Client, topic string) ([]int, error) {
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata of topic %s: %w", topic, err)
+	}
+
+	for _, t := range metadata.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return nil, fmt.Errorf("failed to load metadata of topic %s: %w", topic, t.Error)
+		}
+
+		partitions := make([]int, 0, len(t.Partitions))
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+		sort.Ints(partitions)
+		return partitions, nil
+	}
+
+	return nil, fmt.Errorf("topic %s not found", topic)
+}
+
+// offsetsAtTime возвращает оффсет первого сообщения не раньше at по каждой партиции.
+// Если таких сообщений нет, возвращается конец партиции
+func offsetsAtTime(
+	ctx context.Context,
+	client *kafka.Client,
+	topic string,
+	partitions []int,
+	at time.Time,
+) (map[int]int64, error) {
+	requests := make([]kafka.OffsetRequest, 0, len(partitions))
+	for _, partition := range partitions {
+		requests = append(requests, kafka.TimeOffsetOf(partition, at))
+	}
+	byTime, err := listOffsets(ctx, client, topic, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	requests = requests[:0]
+	for _, partition := range partitions {
+		requests = append(requests, kafka.LastOffsetOf(partition))
+	}
+	last, err := listOffsets(ctx, client, topic, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for _, partition := range partitions {
+		offsets[partition] = last[partition].LastOffset
+		for offset := range byTime[partition].Offsets {
+			if offset >= 0 {
+				offsets[partition] = offset
+			}
+		}
+	}
+	return offsets, nil
+}
+
+func listOffsets(
+	ctx context.Context,
+	client *kafka.Client,
+	topic string,
+	requests []kafka.OffsetRequest,
+) (map[int]kafka.PartitionOffsets, error) {
+	resp, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offsets of topic %s: %w", topic, err)
+	}
+
+	offsets := make(map[int]kafka.PartitionOffsets)
+	for _, partition := range resp.Topics[topic] {
+		if partition.Error != nil {
+			return nil, fmt.Errorf("failed to list offsets of %s partition %d: %w", topic, partition.Partition, partition.Error)
+		}
+		offsets[partition.Partition] = partition
+	}
+	if len(offsets) == 0 {
+		return nil, errors.New("broker returned no offsets")
+	}
+	return offsets, nil
+}
+
+func containsPartition(partitions []int, partition int) bool {
+	for _, p := range partitions {
+		if p == partition {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplayWindow ограничивает консьюмер топика окном повторной обработки:
+// сообщения за верхней границей не обрабатываются, а после границы всех партиций консьюмер останавливается
+type ReplayWindow struct {
+	topic   string
+	bounds  map[int]int64 // последний оффсет окна включительно
+	reached map[int]bool
+}
+
+func newReplayWindow(topic string, starts, bounds map[int]int64) *ReplayWindow {
+	window := &ReplayWindow{
+		topic:   topic,
+		bounds:  bounds,
+		reached: make(map[int]bool, len(bounds)),
+	}
+	// пустое окно: начало уже за границей
+	for partition, bound := range bounds {
+		if starts[partition] > bound {
+			window.reached[partition] = true
+		}
+	}
+	return window
+}
+
+func (w *ReplayWindow) Topic() string {
+	return w.topic
+}
+
+// admit решает, обрабатывать ли сообщение: все, что за границей окна, остается в топике
+// и будет прочитано при следующем обычном запуске
+func (w *ReplayWindow) admit(msg kafka.Message) bool {
+	bound, ok := w.bounds[msg.Partition]
+	if !ok {
+		return true
+	}
+
+	if msg.Offset >= bound {
+		w.reached[msg.Partition] = true
+	}
+	return msg.Offset <= bound
+}
+
+func (w *ReplayWindow) finished() bool {
+	return len(w.reached) == len(w.bounds)
+}
@@ -0,0 +1,94 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestReplayWindowAdmit(t *testing.T) {
+	tests := []struct {
+		name     string
+		starts   map[int]int64
+		bounds   map[int]int64
+		messages []kafka.Message
+		admitted []bool
+		finished bool
+	}{
+		{
+			name:   "messages inside window are admitted",
+			starts: map[int]int64{0: 10},
+			bounds: map[int]int64{0: 12},
+			messages: []kafka.Message{
+				{Partition: 0, Offset: 10},
+				{Partition: 0, Offset: 11},
+			},
+			admitted: []bool{true, true},
+			finished: false,
+		},
+		{
+			name:   "bound offset is admitted and finishes partition",
+			starts: map[int]int64{0: 10},
+			bounds: map[int]int64{0: 12},
+			messages: []kafka.Message{
+				{Partition: 0, Offset: 12},
+			},
+			admitted: []bool{true},
+			finished: true,
+		},
+		{
+			name:   "message past bound is rejected",
+			starts: map[int]int64{0: 10},
+			bounds: map[int]int64{0: 12},
+			messages: []kafka.Message{
+				{Partition: 0, Offset: 13},
+			},
+			admitted: []bool{false},
+			finished: true,
+		},
+		{
+			name:   "partition without bound is not limited",
+			starts: map[int]int64{0: 10, 1: 0},
+			bounds: map[int]int64{0: 12},
+			messages: []kafka.Message{
+				{Partition: 1, Offset: 500},
+			},
+			admitted: []bool{true},
+			finished: false,
+		},
+		{
+			name:   "window finishes only after every bounded partition",
+			starts: map[int]int64{0: 0, 1: 0},
+			bounds: map[int]int64{0: 5, 1: 7},
+			messages: []kafka.Message{
+				{Partition: 0, Offset: 5},
+				{Partition: 1, Offset: 6},
+			},
+			admitted: []bool{true, true},
+			finished: false,
+		},
+		{
+			name:     "empty window is finished from the start",
+			starts:   map[int]int64{0: 20},
+			bounds:   map[int]int64{0: 19},
+			finished: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window := newReplayWindow("orders", tt.starts, tt.bounds)
+
+			for i, msg := range tt.messages {
+				if got := window.admit(msg); got != tt.admitted[i] {
+					t.Errorf("admit(partition %d, offset %d) = %v, want %v",
+						msg.Partition, msg.Offset, got, tt.admitted[i])
+				}
+			}
+
+			if got := window.finished(); got != tt.finished {
+				t.Errorf("finished() = %v, want %v", got, tt.finished)
+			}
+		})
+	}
+}
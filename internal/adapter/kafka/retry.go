@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+// FailureRouter решает судьбу сообщения, которое не удалось обработать:
+// временные ошибки (domain.TransientError) уходят на следующую ступень retry топиков,
+// все остальные и исчерпавшие последнюю ступень - в DLQ
+type FailureRouter struct {
+	tiers  []config.RetryTopicConfig
+	writer *kafka.Writer
+	dlq    *DeadLetterPublisher
+}
+
+func NewFailureRouter(cfg *config.KafkaConfig, dlq *DeadLetterPublisher) (*FailureRouter, error) {
+	security, err := newConnectionSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure kafka connection: %w", err)
+	}
+
+	return &FailureRouter{
+		tiers: cfg.RetryTopics,
+		// топик задается в каждом сообщении
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(cfg.Brokers...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			Transport:              security.transport(),
+		},
+		dlq: dlq,
+	}, nil
+}
+
+func (r *FailureRouter) HandleFailure(ctx context.Context, msg kafka.Message, cause error) error {
+	if !domain.IsTransient(cause) {
+		return r.dlq.Publish(ctx, msg, cause)
+	}
+
+	tier, ok := r.nextTier(msg.Topic)
+	if !ok {
+		log.Printf("Message from %s exhausted retry topics, moving to DLQ", msg.Topic)
+		return r.dlq.Publish(ctx, msg, cause)
+	}
+
+	retry := kafka.Message{
+		Topic:   tier.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: failureHeaders(msg, cause),
+	}
+	if err := r.writer.WriteMessages(ctx, retry); err != nil {
+		return fmt.Errorf("failed to publish message to retry topic %s: %w", tier.Topic, err)
+	}
+
+	log.Printf("Message from partition %d, offset %d scheduled for retry via %s",
+		msg.Partition, msg.Offset, tier.Topic)
+	return nil
+}
+
+// nextTier - сообщение из основного топика идет на первую ступень, из ступени i - на i+1
+func (r *FailureRouter) nextTier(topic string) (config.RetryTopicConfig, bool) {
+	next := 0
+	for i, tier := range r.tiers {
+		if tier.Topic == topic {
+			next = i + 1
+			break
+		}
+	}
+
+	if next >= len(r.tiers) {
+		return config.RetryTopicConfig{}, false
+	}
+	return r.tiers[next], true
+}
+
+func (r *FailureRouter) Close() error {
+	if err := r.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close retry writer: %w", err)
+	}
+	return nil
+}
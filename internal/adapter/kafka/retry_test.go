@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+func TestFailureRouterNextTier(t *testing.T) {
+	router := &FailureRouter{tiers: []config.RetryTopicConfig{
+		{Topic: "notifications.retry.1m", Delay: time.Minute},
+		{Topic: "notifications.retry.10m", Delay: 10 * time.Minute},
+	}}
+
+	tests := []struct {
+		topic  string
+		want   string
+		wantOK bool
+	}{
+		{topic: "order.events", want: "notifications.retry.1m", wantOK: true},
+		{topic: "notifications.retry.1m", want: "notifications.retry.10m", wantOK: true},
+		{topic: "notifications.retry.10m", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			tier, ok := router.nextTier(tt.topic)
+			if ok != tt.wantOK {
+				t.Fatalf("nextTier(%s) ok = %v, want %v", tt.topic, ok, tt.wantOK)
+			}
+			if tier.Topic != tt.want {
+				t.Errorf("nextTier(%s) = %s, want %s", tt.topic, tier.Topic, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureRouterNextTierWithoutTiers(t *testing.T) {
+	router := &FailureRouter{}
+	if tier, ok := router.nextTier("order.events"); ok {
+		t.Errorf("nextTier() = %s, want DLQ", tier.Topic)
+	}
+}
+
+func TestFailureHeaders(t *testing.T) {
+	cause := errors.New("smtp timeout")
+
+	tests := []struct {
+		name         string
+		msg          kafka.Message
+		wantOriginal [3]string // topic, partition, offset
+		wantCount    string
+		wantCustom   bool
+	}{
+		{
+			name: "first failure records original coordinates",
+			msg: kafka.Message{
+				Topic:     "order.events",
+				Partition: 2,
+				Offset:    41,
+				Headers:   []kafka.Header{{Key: "trace-id", Value: []byte("abc")}},
+			},
+			wantOriginal: [3]string{"order.events", "2", "41"},
+			wantCount:    "1",
+			wantCustom:   true,
+		},
+		{
+			name: "retry keeps original coordinates and increments count",
+			msg: kafka.Message{
+				Topic:     "notifications.retry.1m",
+				Partition: 0,
+				Offset:    7,
+				Headers: []kafka.Header{
+					{Key: HeaderOriginalTopic, Value: []byte("order.events")},
+					{Key: HeaderOriginalPartition, Value: []byte("2")},
+					{Key: HeaderOriginalOffset, Value: []byte("41")},
+					{Key: HeaderError, Value: []byte("previous error")},
+					{Key: HeaderFailureCount, Value: []byte("1")},
+					{Key: HeaderFailedAt, Value: []byte("2026-10-01T12:00:00Z")},
+				},
+			},
+			wantOriginal: [3]string{"order.events", "2", "41"},
+			wantCount:    "2",
+		},
+		{
+			name: "malformed failure count starts over",
+			msg: kafka.Message{
+				Topic:   "notifications.retry.10m",
+				Headers: []kafka.Header{{Key: HeaderFailureCount, Value: []byte("many")}},
+			},
+			wantOriginal: [3]string{"notifications.retry.10m", "0", "0"},
+			wantCount:    "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := failureHeaders(tt.msg, cause)
+			got := kafka.Message{Headers: headers}
+
+			for i, key := range []string{HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset} {
+				if value, _ := headerValue(got, key); value != tt.wantOriginal[i] {
+					t.Errorf("%s = %q, want %q", key, value, tt.wantOriginal[i])
+				}
+			}
+			if value, _ := headerValue(got, HeaderFailureCount); value != tt.wantCount {
+				t.Errorf("%s = %q, want %q", HeaderFailureCount, value, tt.wantCount)
+			}
+			if value, _ := headerValue(got, HeaderError); value != cause.Error() {
+				t.Errorf("%s = %q, want %q", HeaderError, value, cause.Error())
+			}
+			if value, _ := headerValue(got, HeaderFailedAt); value == "" {
+				t.Errorf("%s is missing", HeaderFailedAt)
+			}
+			if _, ok := headerValue(got, "trace-id"); ok != tt.wantCustom {
+				t.Errorf("trace-id present = %v, want %v", ok, tt.wantCustom)
+			}
+
+			seen := make(map[string]bool)
+			for _, h := range headers {
+				if seen[h.Key] {
+					t.Errorf("header %s is duplicated", h.Key)
+				}
+				seen[h.Key] = true
+			}
+		})
+	}
+}
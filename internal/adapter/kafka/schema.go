@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrSchemaValidation - событие не соответствует своей JSON Schema
+var ErrSchemaValidation = errors.New("event does not match its schema")
+
+// SchemaValidator проверяет события по JSON Schema из каталога схем.
+// Файл схемы называется <event_type>.v<schema_version>.json, общие части (envelope.json)
+// подключаются через $ref
+type SchemaValidator struct {
+	schemas map[schemaKey]*jsonschema.Schema
+}
+
+type schemaKey struct {
+	eventType EventType
+	version   int
+}
+
+// SchemaViolation - нарушение схемы в конкретном поле события
+type SchemaViolation struct {
+	Field   string // JSON pointer поля, "/" - само событие
+	Message string
+}
+
+// SchemaError перечисляет все нарушения схемы. Повтор не поможет, поэтому сообщение уходит в DLQ
+type SchemaError struct {
+	EventType  EventType
+	Version    int
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	return fmt.Sprintf("%s v%d: %s", e.EventType, e.Version, strings.Join(parts, "; "))
+}
+
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaValidation
+}
+
+func NewSchemaValidator(dir string) (*SchemaValidator, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.v*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event schemas: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no event schemas found in %s", dir)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+
+	validator := &SchemaValidator{schemas: make(map[schemaKey]*jsonschema.Schema)}
+	for _, file := range files {
+		key, err := parseSchemaFileName(filepath.Base(file))
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema path %s: %w", file, err)
+		}
+
+		schema, err := compiler.Compile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+		}
+		validator.schemas[key] = schema
+	}
+
+	return validator, nil
+}
+
+// parseSchemaFileName разбирает имя вида user.email.verification.requested.v1.json
+func parseSchemaFileName(name string) (schemaKey, error) {
+	base := strings.TrimSuffix(name, ".json")
+	idx := strings.LastIndex(base, ".v")
+	if idx <= 0 {
+		return schemaKey{}, fmt.Errorf("invalid schema file name %s, expected <event_type>.v<version>.json", name)
+	}
+
+	version, err := strconv.Atoi(base[idx+2:])
+	if err != nil || version < 1 {
+		return schemaKey{}, fmt.Errorf("invalid schema version in file name %s", name)
+	}
+
+	return schemaKey{eventType: EventType(base[:idx]), version: version}, nil
+}
+
+// Covers проверяет, что для каждого типа событий есть хотя бы одна схема
+func (v *SchemaValidator) Covers(eventTypes []EventType) error {
+	for _, eventType := range eventTypes {
+		found := false
+		for key := range v.schemas {
+			if key.eventType == eventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no schema for event type %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Validate проверяет сообщение целиком: заголовок и поля события
+func (v *SchemaValidator) Validate(eventType EventType, version int, data []byte) error {
+	schema, ok := v.schemas[schemaKey{eventType: eventType, version: version}]
+	if !ok {
+		return &SchemaError{
+			EventType:  eventType,
+			Version:    version,
+			Violations: []SchemaViolation{{Field: "/schema_version", Message: "unsupported schema version"}},
+		}
+	}
+
+	// числа сохраняются как json.Number, чтобы валидатор различал integer и number
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return fmt.Errorf("failed to decode event for schema validation: %w", err)
+	}
+
+	err := schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return fmt.Errorf("failed to validate event: %w", err)
+	}
+
+	return &SchemaError{
+		EventType:  eventType,
+		Version:    version,
+		Violations: collectViolations(validationErr),
+	}
+}
+
+// collectViolations оставляет только конечные причины: верхние уровни дерева ошибок
+// вида "doesn't validate with ..." ничего не говорят о конкретном поле
+func collectViolations(err *jsonschema.ValidationError) []SchemaViolation {
+	var violations []SchemaViolation
+
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			field := e.InstanceLocation
+			if field == "" {
+				field = "/"
+			}
+			violations = append(violations, SchemaViolation{Field: field, Message: e.Message})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(err)
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+	return violations
+}
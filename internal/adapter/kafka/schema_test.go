@@ -0,0 +1,330 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const schemasDir = "../../../assets/schemas/events"
+
+const (
+	testUserID   = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	testSellerID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+)
+
+func TestParseSchemaFileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    schemaKey
+		wantErr bool
+	}{
+		{name: "order.created.v1.json", want: schemaKey{eventType: "order.created", version: 1}},
+		{name: "user.notification.chat.message.v12.json", want: schemaKey{eventType: "user.notification.chat.message", version: 12}},
+		{name: "order.created.json", wantErr: true},
+		{name: ".v1.json", wantErr: true},
+		{name: "order.created.v0.json", wantErr: true},
+		{name: "order.created.vx.json", wantErr: true},
+		{name: "order.created.v-1.json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSchemaFileName(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseSchemaFileName(%q) = %+v, want error", tt.name, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSchemaFileName(%q) unexpected error: %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseSchemaFileName(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// event собирает событие из заголовка и полей, nil в fields удаляет поле
+func event(eventType EventType, fields map[string]any) []byte {
+	payload := map[string]any{
+		"event_id":       "evt-1",
+		"event_type":     string(eventType),
+		"schema_version": 1,
+		"occurred_at":    "2026-10-01T12:00:00Z",
+		"producer":       "test",
+	}
+	for key, value := range fields {
+		if value == nil {
+			delete(payload, key)
+			continue
+		}
+		payload[key] = value
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func newTestValidator(t *testing.T) *SchemaValidator {
+	t.Helper()
+	validator, err := NewSchemaValidator(schemasDir)
+	if err != nil {
+		t.Fatalf("NewSchemaValidator() error: %v", err)
+	}
+	return validator
+}
+
+func TestSchemaValidatorValidate(t *testing.T) {
+	validator := newTestValidator(t)
+
+	order := map[string]any{
+		"order_id":  "order-1",
+		"buyer_id":  testUserID,
+		"seller_id": testSellerID,
+		"amount":    100,
+	}
+	with := func(base map[string]any, key string, value any) map[string]any {
+		fields := make(map[string]any, len(base)+1)
+		for k, v := range base {
+			fields[k] = v
+		}
+		fields[key] = value
+		return fields
+	}
+
+	tests := []struct {
+		name      string
+		eventType EventType
+		fields    map[string]any
+		wantField string // пусто - событие должно пройти проверку
+	}{
+		{
+			name:      "email verification is valid",
+			eventType: EventTypeEmailVerification,
+			fields: map[string]any{
+				"user_id":           testUserID,
+				"email":             "user@example.com",
+				"confirmation_code": "123456",
+				"expires_at":        "2026-10-01T13:00:00Z",
+			},
+		},
+		{
+			name:      "email verification with invalid email",
+			eventType: EventTypeEmailVerification,
+			fields: map[string]any{
+				"user_id":           testUserID,
+				"email":             "not-an-email",
+				"confirmation_code": "123456",
+				"expires_at":        "2026-10-01T13:00:00Z",
+			},
+			wantField: "/email",
+		},
+		{
+			name:      "chat message with multiline sender name",
+			eventType: EventTypeChatMessage,
+			fields: map[string]any{
+				"user_id":     testUserID,
+				"chat_id":     "chat-1",
+				"sender_id":   testSellerID,
+				"sender_name": "Ivan\r\nBcc: victim@example.com",
+			},
+			wantField: "/sender_name",
+		},
+		{
+			name:      "listing price change without prices",
+			eventType: EventTypeListingUpdate,
+			fields: map[string]any{
+				"user_id":     testUserID,
+				"listing_id":  "listing-1",
+				"change_kind": "price_changed",
+			},
+			wantField: "/",
+		},
+		{
+			name:      "review rating out of range",
+			eventType: EventTypeReviewReceived,
+			fields: map[string]any{
+				"user_id":   testUserID,
+				"review_id": "review-1",
+				"rating":    6,
+			},
+			wantField: "/rating",
+		},
+		{
+			name:      "order created with negative amount",
+			eventType: EventTypeOrderCreated,
+			fields:    with(order, "amount", -1),
+			wantField: "/amount",
+		},
+		{
+			name:      "order status changed with unknown status",
+			eventType: EventTypeOrderStatusChanged,
+			fields:    with(order, "status", "lost"),
+			wantField: "/status",
+		},
+		{
+			name:      "cancel without target event",
+			eventType: EventTypeNotificationCancel,
+			fields:    map[string]any{"user_id": testUserID},
+			wantField: "/",
+		},
+		{
+			name:      "legacy event without schema version and with timestamp",
+			eventType: EventTypeNotificationCancel,
+			fields: map[string]any{
+				"target_event_id": "evt-0",
+				"schema_version":  nil,
+				"occurred_at":     nil,
+				"producer":        nil,
+				"timestamp":       "2026-10-01T12:00:00Z",
+			},
+		},
+		{
+			name:      "event without occurred_at and timestamp",
+			eventType: EventTypeNotificationCancel,
+			fields: map[string]any{
+				"target_event_id": "evt-0",
+				"occurred_at":     nil,
+			},
+			wantField: "/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.eventType, 1, event(tt.eventType, tt.fields))
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("Validate() error = %v, want *SchemaError", err)
+			}
+			if !errors.Is(err, ErrSchemaValidation) {
+				t.Errorf("Validate() error does not wrap ErrSchemaValidation")
+			}
+			found := false
+			for _, violation := range schemaErr.Violations {
+				if violation.Field == tt.wantField {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Validate() violations = %+v, want one for %s", schemaErr.Violations, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestSchemaValidatorUnsupportedVersion(t *testing.T) {
+	validator := newTestValidator(t)
+
+	err := validator.Validate(EventTypeOrderCreated, 2, event(EventTypeOrderCreated, nil))
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Validate() error = %v, want *SchemaError", err)
+	}
+	if len(schemaErr.Violations) != 1 || schemaErr.Violations[0].Field != "/schema_version" {
+		t.Errorf("Validate() violations = %+v, want /schema_version", schemaErr.Violations)
+	}
+}
+
+func TestCollectViolations(t *testing.T) {
+	validator := newTestValidator(t)
+
+	// нарушения в нескольких полях возвращаются только конечными причинами и по порядку полей
+	err := validator.Validate(EventTypeEmailVerification, 1, event(EventTypeEmailVerification, map[string]any{
+		"user_id":           "not-a-uuid",
+		"email":             "not-an-email",
+		"confirmation_code": "",
+		"expires_at":        "2026-10-01T13:00:00Z",
+	}))
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Validate() error = %v, want *SchemaError", err)
+	}
+
+	want := []string{"/confirmation_code", "/email", "/user_id"}
+	if len(schemaErr.Violations) != len(want) {
+		t.Fatalf("violations = %+v, want fields %v", schemaErr.Violations, want)
+	}
+	for i, field := range want {
+		if schemaErr.Violations[i].Field != field {
+			t.Errorf("violation %d field = %s, want %s", i, schemaErr.Violations[i].Field, field)
+		}
+		if schemaErr.Violations[i].Message == "" {
+			t.Errorf("violation %d has empty message", i)
+		}
+	}
+}
+
+func TestEnvelopeValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantErr     bool
+		wantVersion int
+		wantTime    bool
+	}{
+		{
+			name:        "current format",
+			data:        `{"event_id":"1","event_type":"order.created","schema_version":2,"occurred_at":"2026-10-01T12:00:00Z","producer":"orders"}`,
+			wantVersion: 2,
+			wantTime:    true,
+		},
+		{
+			name:        "legacy format",
+			data:        `{"event_id":"1","event_type":"order.created","timestamp":"2026-10-01T12:00:00Z"}`,
+			wantVersion: legacySchemaVersion,
+			wantTime:    true,
+		},
+		{
+			name:    "negative schema version",
+			data:    `{"event_id":"1","event_type":"order.created","schema_version":-1}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing event id",
+			data:    `{"event_type":"order.created","schema_version":1}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var envelope Envelope
+			if err := json.Unmarshal([]byte(tt.data), &envelope); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			err := envelope.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Validate() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() unexpected error: %v", err)
+			}
+			if envelope.SchemaVersion != tt.wantVersion {
+				t.Errorf("SchemaVersion = %d, want %d", envelope.SchemaVersion, tt.wantVersion)
+			}
+			if tt.wantTime && envelope.OccurredAt.IsZero() {
+				t.Errorf("OccurredAt is zero, want event time")
+			}
+		})
+	}
+}
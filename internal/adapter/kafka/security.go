@@ -0,0 +1,139 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+// connectionSecurity - TLS и SASL настройки подключения к брокерам,
+// общие для reader'ов консьюмеров и writer'ов DLQ и retry топиков
+type connectionSecurity struct {
+	tls         *tls.Config
+	sasl        sasl.Mechanism
+	dialTimeout time.Duration
+}
+
+func newConnectionSecurity(cfg *config.KafkaConfig) (*connectionSecurity, error) {
+	security := &connectionSecurity{dialTimeout: cfg.DialTimeout}
+
+	switch cfg.SecurityProtocol {
+	case config.KafkaProtocolPlaintext:
+	case config.KafkaProtocolSSL:
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		security.tls = tlsConfig
+	case config.KafkaProtocolSASLPlaintext:
+		mechanism, err := newSASLMechanism(cfg)
+		if err != nil {
+			return nil, err
+		}
+		security.sasl = mechanism
+	case config.KafkaProtocolSASLSSL:
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		mechanism, err := newSASLMechanism(cfg)
+		if err != nil {
+			return nil, err
+		}
+		security.tls, security.sasl = tlsConfig, mechanism
+	default:
+		return nil, fmt.Errorf("unsupported kafka security protocol %q", cfg.SecurityProtocol)
+	}
+
+	return security, nil
+}
+
+func (s *connectionSecurity) dialer() *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:       s.dialTimeout,
+		DualStack:     true,
+		TLS:           s.tls,
+		SASLMechanism: s.sasl,
+	}
+}
+
+func (s *connectionSecurity) transport() *kafka.Transport {
+	return &kafka.Transport{
+		DialTimeout: s.dialTimeout,
+		TLS:         s.tls,
+		SASL:        s.sasl,
+	}
+}
+
+func newTLSConfig(cfg *config.KafkaConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+	}
+
+	// без CA файла используются системные корневые сертификаты
+	if cfg.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in kafka CA file %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// клиентский сертификат нужен только брокерам с mTLS
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, errors.New("both kafka TLS cert and key files are required for client authentication")
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func newSASLMechanism(cfg *config.KafkaConfig) (sasl.Mechanism, error) {
+	if cfg.SASLUsername == "" {
+		return nil, errors.New("kafka SASL username is required")
+	}
+
+	switch cfg.SASLMechanism {
+	case config.KafkaSASLPlain:
+		return plain.Mechanism{
+			Username: cfg.SASLUsername,
+			Password: cfg.SASLPassword,
+		}, nil
+	case config.KafkaSASLScramSHA256:
+		mechanism, err := scram.Mechanism(scram.SHA256, cfg.SASLUsername, cfg.SASLPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SCRAM-SHA-256 mechanism: %w", err)
+		}
+		return mechanism, nil
+	case config.KafkaSASLScramSHA512:
+		mechanism, err := scram.Mechanism(scram.SHA512, cfg.SASLUsername, cfg.SASLPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SCRAM-SHA-512 mechanism: %w", err)
+		}
+		return mechanism, nil
+	default:
+		return nil, fmt.Errorf("unsupported kafka SASL mechanism %q", cfg.SASLMechanism)
+	}
+}
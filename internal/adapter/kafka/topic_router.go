@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// TopicRouter направляет сообщение обработчику его топика.
+// Сообщения из retry топиков идут обработчику исходного топика (заголовок x-original-topic)
+type TopicRouter struct {
+	handlers map[string]MessageHandler
+}
+
+func NewTopicRouter() *TopicRouter {
+	return &TopicRouter{
+		handlers: make(map[string]MessageHandler),
+	}
+}
+
+func (r *TopicRouter) Route(topic string, handler MessageHandler) {
+	if topic == "" {
+		return
+	}
+	r.handlers[topic] = handler
+}
+
+// Topics возвращает топики, для которых есть обработчик
+func (r *TopicRouter) Topics() []string {
+	topics := make([]string, 0, len(r.handlers))
+	for topic := range r.handlers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func (r *TopicRouter) Handle(ctx context.Context, message kafka.Message) error {
+	topic := message.Topic
+	if original, ok := headerValue(message, HeaderOriginalTopic); ok {
+		topic = original
+	}
+
+	handler, ok := r.handlers[topic]
+	if !ok {
+		return fmt.Errorf("no handler registered for topic %s", topic)
+	}
+
+	return handler.Handle(ctx, message)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+var (
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrUnknownSigningKey = errors.New("unknown signing key")
+)
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Role  string   `json:"role,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// JWTVerifier проверяет bearer токены: HS256 по общему секрету и RS256 по ключам из JWKS
+type JWTVerifier struct {
+	secret    []byte
+	keys      map[string]*rsa.PublicKey
+	adminRole string
+	parser    *jwt.Parser
+}
+
+func NewJWTVerifier(cfg *config.AuthConfig) (*JWTVerifier, error) {
+	methods := make([]string, 0, 2)
+	verifier := &JWTVerifier{
+		adminRole: cfg.AdminRole,
+	}
+
+	if cfg.JWTSecret != "" {
+		verifier.secret = []byte(cfg.JWTSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSPath != "" {
+		keys, err := loadJWKS(cfg.JWKSPath)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("no JWT verification keys configured")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	verifier.parser = jwt.NewParser(options...)
+
+	return verifier, nil
+}
+
+func (v *JWTVerifier) Verify(tokenString string) (*domain.Principal, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(tokenString, &claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: subject is not a user UUID", ErrInvalidToken)
+	}
+
+	roles := claims.Roles
+	if claims.Role != "" && !slices.Contains(roles, claims.Role) {
+		roles = append(roles, claims.Role)
+	}
+
+	return &domain.Principal{
+		UserID:  userID,
+		Roles:   roles,
+		IsAdmin: v.adminRole != "" && slices.Contains(roles, v.adminRole),
+	}, nil
+}
+
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.secret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		// токен без kid допустим, только если ключ в JWKS единственный
+		if kid == "" && len(v.keys) == 1 {
+			for _, key := range v.keys {
+				return key, nil
+			}
+		}
+		return nil, ErrUnknownSigningKey
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+}
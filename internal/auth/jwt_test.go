@@ -0,0 +1,381 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+const (
+	testSecret  = "test-secret"
+	testSubject = "0f8fad5b-d9cb-469f-a165-70867728950e"
+)
+
+func validClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   testSubject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// writeJWKS сохраняет публичные ключи в JWKS файл во временном каталоге
+func writeJWKS(t *testing.T, keys map[string]*rsa.PublicKey) string {
+	t.Helper()
+	var set jwks
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+	return path
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+func TestJWTVerifierHS256(t *testing.T) {
+	verifier, err := NewJWTVerifier(&config.AuthConfig{
+		JWTSecret: testSecret,
+		Issuer:    "user-service",
+		Audience:  "notification-service",
+		AdminRole: "admin",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error: %v", err)
+	}
+
+	withAudience := func(mutate func(c *Claims)) Claims {
+		claims := validClaims()
+		claims.Issuer = "user-service"
+		claims.Audience = jwt.ClaimStrings{"notification-service"}
+		if mutate != nil {
+			mutate(&claims)
+		}
+		return claims
+	}
+	rsaKey := generateKey(t)
+
+	tests := []struct {
+		name      string
+		token     string
+		wantErr   bool
+		wantAdmin bool
+	}{
+		{
+			name:  "valid token",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(nil)),
+		},
+		{
+			name: "admin role",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(func(c *Claims) {
+				c.Roles = []string{"user", "admin"}
+			})),
+			wantAdmin: true,
+		},
+		{
+			name:    "bad signature",
+			token:   sign(t, jwt.SigningMethodHS256, []byte("other-secret"), "", withAudience(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "wrong alg HS384",
+			token:   sign(t, jwt.SigningMethodHS384, []byte(testSecret), "", withAudience(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "wrong alg RS256 without JWKS",
+			token:   sign(t, jwt.SigningMethodRS256, rsaKey, "", withAudience(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "alg none",
+			token:   sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", withAudience(nil)),
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(func(c *Claims) {
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+			})),
+			wantErr: true,
+		},
+		{
+			name: "missing expiry",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(func(c *Claims) {
+				c.ExpiresAt = nil
+			})),
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(func(c *Claims) {
+				c.Issuer = "someone-else"
+			})),
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(func(c *Claims) {
+				c.Audience = jwt.ClaimStrings{"billing-service"}
+			})),
+			wantErr: true,
+		},
+		{
+			name: "subject is not a UUID",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", withAudience(func(c *Claims) {
+				c.Subject = "user-1"
+			})),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if principal.UserID.String() != testSubject {
+				t.Errorf("UserID = %s, want %s", principal.UserID, testSubject)
+			}
+			if principal.IsAdmin != tt.wantAdmin {
+				t.Errorf("IsAdmin = %v, want %v", principal.IsAdmin, tt.wantAdmin)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierRS256(t *testing.T) {
+	primary := generateKey(t)
+	secondary := generateKey(t)
+	foreign := generateKey(t)
+
+	multiKey, err := NewJWTVerifier(&config.AuthConfig{
+		JWKSPath: writeJWKS(t, map[string]*rsa.PublicKey{
+			"primary":   &primary.PublicKey,
+			"secondary": &secondary.PublicKey,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error: %v", err)
+	}
+
+	singleKey, err := NewJWTVerifier(&config.AuthConfig{
+		JWKSPath: writeJWKS(t, map[string]*rsa.PublicKey{"primary": &primary.PublicKey}),
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error: %v", err)
+	}
+
+	// подпись HS256 публичным ключом - классическая подмена алгоритма
+	publicKeyBytes := primary.PublicKey.N.Bytes()
+
+	tests := []struct {
+		name     string
+		verifier *JWTVerifier
+		token    string
+		wantErr  bool
+	}{
+		{
+			name:     "valid token with kid",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS256, primary, "primary", validClaims()),
+		},
+		{
+			name:     "second key in set",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS256, secondary, "secondary", validClaims()),
+		},
+		{
+			name:     "unknown kid",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS256, primary, "retired", validClaims()),
+			wantErr:  true,
+		},
+		{
+			name:     "missing kid with several keys",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS256, primary, "", validClaims()),
+			wantErr:  true,
+		},
+		{
+			name:     "missing kid with single key",
+			verifier: singleKey,
+			token:    sign(t, jwt.SigningMethodRS256, primary, "", validClaims()),
+		},
+		{
+			name:     "bad signature",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS256, foreign, "primary", validClaims()),
+			wantErr:  true,
+		},
+		{
+			name:     "kid of another key",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS256, primary, "secondary", validClaims()),
+			wantErr:  true,
+		},
+		{
+			name:     "HS256 with public key as secret",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodHS256, publicKeyBytes, "primary", validClaims()),
+			wantErr:  true,
+		},
+		{
+			name:     "wrong alg RS512",
+			verifier: multiKey,
+			token:    sign(t, jwt.SigningMethodRS512, primary, "primary", validClaims()),
+			wantErr:  true,
+		},
+		{
+			name:     "expired",
+			verifier: multiKey,
+			token: sign(t, jwt.SigningMethodRS256, primary, "primary", func() Claims {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return claims
+			}()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := tt.verifier.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if principal.UserID.String() != testSubject {
+				t.Errorf("UserID = %s, want %s", principal.UserID, testSubject)
+			}
+		})
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	key := generateKey(t)
+	valid := jwk{
+		Kty: "RSA",
+		Kid: "primary",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		wantKeys int
+		wantErr  bool
+	}{
+		{
+			name:     "skips non RSA and encryption keys",
+			content:  mustJSON(t, jwks{Keys: []jwk{valid, {Kty: "EC", Kid: "ec"}, {Kty: "RSA", Kid: "enc", Use: "enc"}}}),
+			wantKeys: 1,
+		},
+		{
+			name:    "no signing keys",
+			content: mustJSON(t, jwks{Keys: []jwk{{Kty: "EC", Kid: "ec"}}}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid exponent",
+			content: mustJSON(t, jwks{Keys: []jwk{{Kty: "RSA", Kid: "bad", N: valid.N, E: "AQ"}}}),
+			wantErr: true,
+		},
+		{
+			name:    "malformed JSON",
+			content: "{",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write JWKS: %v", err)
+			}
+
+			keys, err := loadJWKS(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("loadJWKS() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadJWKS() unexpected error: %v", err)
+			}
+			if len(keys) != tt.wantKeys {
+				t.Errorf("loadJWKS() returned %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+}
+
+func mustJSON(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	return string(data)
+}
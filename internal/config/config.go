@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,15 +19,21 @@ func init() {
 }
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Kafka    KafkaConfig
-	Email    EmailConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Kafka     KafkaConfig
+	Email     EmailConfig
+	Auth      AuthConfig
+	Retry     RetryConfig
+	Outbox    OutboxConfig
+	Scheduler SchedulerConfig
+	Digest    DigestConfig
 }
 
 type ServerConfig struct {
-	Address  string
-	LogLevel string
+	Address              string
+	LogLevel             string
+	SSEHeartbeatInterval time.Duration
 }
 
 type DatabaseConfig struct {
@@ -33,11 +42,80 @@ type DatabaseConfig struct {
 }
 
 type KafkaConfig struct {
-	Brokers         []string
-	TopicUserEvents string
-	GroupID         string
-	MinBytes        int
-	MaxBytes        int
+	Brokers            []string
+	TopicUserEvents    string
+	TopicOrderEvents   string
+	TopicChatEvents    string
+	TopicListingEvents string
+	DLQTopic           string
+	OutcomeTopic       string // итоги доставки уведомлений для других сервисов
+	RetryTopics        []RetryTopicConfig
+	GroupID            string
+	// UnknownEventsToDLQ - события незарегистрированных типов уходят в DLQ, иначе пропускаются
+	UnknownEventsToDLQ bool
+	// Workers - число параллельных обработчиков сообщений на топик
+	Workers  int
+	MinBytes int
+	MaxBytes int
+	// StartOffset - откуда читать топик новой consumer group: first | last.
+	// На группы с закоммиченными оффсетами не влияет
+	StartOffset string
+	Replay      ReplayConfig
+	// SchemasPath - каталог JSON Schema событий
+	SchemasPath string
+
+	DialTimeout           time.Duration
+	SecurityProtocol      string // PLAINTEXT | SSL | SASL_PLAINTEXT | SASL_SSL
+	SASLMechanism         string // PLAIN | SCRAM-SHA-256 | SCRAM-SHA-512
+	SASLUsername          string
+	SASLPassword          string
+	TLSCAFile             string
+	TLSCertFile           string // клиентский сертификат для mTLS, необязателен
+	TLSKeyFile            string
+	TLSInsecureSkipVerify bool // только для локальной отладки
+}
+
+const (
+	KafkaProtocolPlaintext     = "PLAINTEXT"
+	KafkaProtocolSSL           = "SSL"
+	KafkaProtocolSASLPlaintext = "SASL_PLAINTEXT"
+	KafkaProtocolSASLSSL       = "SASL_SSL"
+
+	KafkaSASLPlain       = "PLAIN"
+	KafkaSASLScramSHA256 = "SCRAM-SHA-256"
+	KafkaSASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// ReplayConfig - повторная обработка окна событий одного топика: перед запуском консьюмеров
+// оффсеты группы сдвигаются на начало окна. Окно задается либо временем (FromTime, UntilTime),
+// либо диапазонами оффсетов по партициям (Offsets)
+type ReplayConfig struct {
+	Topic     string
+	FromTime  time.Time
+	UntilTime time.Time // необязательна, без нее после окна обработка продолжается как обычно
+	Offsets   map[int]OffsetRange
+}
+
+func (r ReplayConfig) Enabled() bool {
+	return r.Topic != ""
+}
+
+// OffsetRange - оффсеты партиции включительно, End < 0 - без верхней границы
+type OffsetRange struct {
+	Start int64
+	End   int64
+}
+
+const (
+	KafkaStartOffsetFirst = "first"
+	KafkaStartOffsetLast  = "last"
+)
+
+// RetryTopicConfig - ступень отложенного повтора: сообщение из Topic обрабатывается не раньше,
+// чем через Delay после публикации
+type RetryTopicConfig struct {
+	Topic string
+	Delay time.Duration
 }
 
 type EmailConfig struct {
@@ -49,6 +127,43 @@ type EmailConfig struct {
 	FromName      string
 	FromAddress   string
 	TemplatesPath string
+	AppBaseURL    string // адрес веб-клиента для ссылок в письмах
+}
+
+type AuthConfig struct {
+	JWTSecret string // HS256, общий секрет с user-service
+	JWKSPath  string // RS256, локальный файл с публичными ключами в формате JWKS
+	Issuer    string
+	Audience  string
+	AdminRole string
+}
+
+type RetryConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Jitter       float64       // доля случайного разброса задержки, 0.2 = ±20%
+	LockTimeout  time.Duration // аренда попыток, должна с запасом покрывать обработку всей пачки
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	LockTimeout  time.Duration // аренда записи, должна с запасом покрывать одну доставку
+}
+
+type SchedulerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+type DigestConfig struct {
+	PollInterval time.Duration
+	BatchSize    int           // сколько пользователей обрабатывается за один забор
+	RetryDelay   time.Duration // пауза перед первым повтором неудавшейся сводки, дальше растет по RETRY_*
+	LockTimeout  time.Duration // аренда уведомлений сводки, должна с запасом покрывать обработку всей пачки
 }
 
 func LoadConfig() (*Config, error) {
@@ -58,21 +173,52 @@ func LoadConfig() (*Config, error) {
 
 	setDefaults()
 
+	retryTopics, err := parseRetryTopics(viper.GetString("KAFKA_RETRY_TOPICS"))
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := parseReplay()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Address:  viper.GetString("SERVER_ADDRESS"),
-			LogLevel: viper.GetString("LOG_LEVEL"),
+			Address:              viper.GetString("SERVER_ADDRESS"),
+			LogLevel:             viper.GetString("LOG_LEVEL"),
+			SSEHeartbeatInterval: viper.GetDuration("SSE_HEARTBEAT_INTERVAL"),
 		},
 		Database: DatabaseConfig{
 			URL:            viper.GetString("DATABASE_URL"),
 			MigrationsPath: viper.GetString("MIGRATIONS_PATH"),
 		},
 		Kafka: KafkaConfig{
-			Brokers:         []string{viper.GetString("KAFKA_BROKERS")},
-			TopicUserEvents: viper.GetString("KAFKA_TOPIC_USER_EVENTS"),
-			GroupID:         viper.GetString("KAFKA_GROUP_ID"),
-			MinBytes:        viper.GetInt("KAFKA_MIN_BYTES"),
-			MaxBytes:        viper.GetInt("KAFKA_MAX_BYTES"),
+			Brokers:               []string{viper.GetString("KAFKA_BROKERS")},
+			TopicUserEvents:       viper.GetString("KAFKA_TOPIC_USER_EVENTS"),
+			TopicOrderEvents:      viper.GetString("KAFKA_TOPIC_ORDER_EVENTS"),
+			TopicChatEvents:       viper.GetString("KAFKA_TOPIC_CHAT_EVENTS"),
+			TopicListingEvents:    viper.GetString("KAFKA_TOPIC_LISTING_EVENTS"),
+			DLQTopic:              viper.GetString("KAFKA_DLQ_TOPIC"),
+			OutcomeTopic:          viper.GetString("KAFKA_OUTCOME_TOPIC"),
+			RetryTopics:           retryTopics,
+			GroupID:               viper.GetString("KAFKA_GROUP_ID"),
+			UnknownEventsToDLQ:    viper.GetBool("KAFKA_UNKNOWN_EVENTS_TO_DLQ"),
+			Workers:               viper.GetInt("KAFKA_WORKERS"),
+			MinBytes:              viper.GetInt("KAFKA_MIN_BYTES"),
+			MaxBytes:              viper.GetInt("KAFKA_MAX_BYTES"),
+			StartOffset:           strings.ToLower(viper.GetString("KAFKA_START_OFFSET")),
+			Replay:                replay,
+			SchemasPath:           viper.GetString("KAFKA_SCHEMAS_PATH"),
+			DialTimeout:           viper.GetDuration("KAFKA_DIAL_TIMEOUT"),
+			SecurityProtocol:      strings.ToUpper(viper.GetString("KAFKA_SECURITY_PROTOCOL")),
+			SASLMechanism:         strings.ToUpper(viper.GetString("KAFKA_SASL_MECHANISM")),
+			SASLUsername:          viper.GetString("KAFKA_SASL_USERNAME"),
+			SASLPassword:          viper.GetString("KAFKA_SASL_PASSWORD"),
+			TLSCAFile:             viper.GetString("KAFKA_TLS_CA_FILE"),
+			TLSCertFile:           viper.GetString("KAFKA_TLS_CERT_FILE"),
+			TLSKeyFile:            viper.GetString("KAFKA_TLS_KEY_FILE"),
+			TLSInsecureSkipVerify: viper.GetBool("KAFKA_TLS_INSECURE_SKIP_VERIFY"),
 		},
 		Email: EmailConfig{
 			Provider:      viper.GetString("EMAIL_PROVIDER"),
@@ -83,6 +229,38 @@ func LoadConfig() (*Config, error) {
 			FromName:      viper.GetString("EMAIL_FROM_NAME"),
 			FromAddress:   viper.GetString("EMAIL_FROM_ADDRESS"),
 			TemplatesPath: viper.GetString("EMAIL_TEMPLATES_PATH"),
+			AppBaseURL:    strings.TrimRight(viper.GetString("EMAIL_APP_BASE_URL"), "/"),
+		},
+		Auth: AuthConfig{
+			JWTSecret: viper.GetString("AUTH_JWT_SECRET"),
+			JWKSPath:  viper.GetString("AUTH_JWKS_PATH"),
+			Issuer:    viper.GetString("AUTH_JWT_ISSUER"),
+			Audience:  viper.GetString("AUTH_JWT_AUDIENCE"),
+			AdminRole: viper.GetString("AUTH_ADMIN_ROLE"),
+		},
+		Retry: RetryConfig{
+			PollInterval: viper.GetDuration("RETRY_POLL_INTERVAL"),
+			BatchSize:    viper.GetInt("RETRY_BATCH_SIZE"),
+			MaxAttempts:  viper.GetInt("RETRY_MAX_ATTEMPTS"),
+			BaseDelay:    viper.GetDuration("RETRY_BASE_DELAY"),
+			MaxDelay:     viper.GetDuration("RETRY_MAX_DELAY"),
+			Jitter:       viper.GetFloat64("RETRY_JITTER"),
+			LockTimeout:  viper.GetDuration("RETRY_LOCK_TIMEOUT"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: viper.GetDuration("OUTBOX_POLL_INTERVAL"),
+			BatchSize:    viper.GetInt("OUTBOX_BATCH_SIZE"),
+			LockTimeout:  viper.GetDuration("OUTBOX_LOCK_TIMEOUT"),
+		},
+		Scheduler: SchedulerConfig{
+			PollInterval: viper.GetDuration("SCHEDULER_POLL_INTERVAL"),
+			BatchSize:    viper.GetInt("SCHEDULER_BATCH_SIZE"),
+		},
+		Digest: DigestConfig{
+			PollInterval: viper.GetDuration("DIGEST_POLL_INTERVAL"),
+			BatchSize:    viper.GetInt("DIGEST_BATCH_SIZE"),
+			RetryDelay:   viper.GetDuration("DIGEST_RETRY_DELAY"),
+			LockTimeout:  viper.GetDuration("DIGEST_LOCK_TIMEOUT"),
 		},
 	}
 
@@ -96,18 +274,142 @@ func LoadConfig() (*Config, error) {
 func setDefaults() {
 	viper.SetDefault("SERVER_ADDRESS", ":8082")
 	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("SSE_HEARTBEAT_INTERVAL", 15*time.Second)
 	viper.SetDefault("MIGRATIONS_PATH", "migrations")
 	viper.SetDefault("KAFKA_GROUP_ID", "notification-service")
+	viper.SetDefault("KAFKA_TOPIC_ORDER_EVENTS", "order.events")
+	viper.SetDefault("KAFKA_TOPIC_CHAT_EVENTS", "chat.events")
+	viper.SetDefault("KAFKA_TOPIC_LISTING_EVENTS", "listing.events")
+	viper.SetDefault("KAFKA_DLQ_TOPIC", "notifications.dlq")
+	viper.SetDefault("KAFKA_OUTCOME_TOPIC", "notification.events")
+	viper.SetDefault("KAFKA_UNKNOWN_EVENTS_TO_DLQ", false)
+	viper.SetDefault("KAFKA_WORKERS", 8)
+	viper.SetDefault("KAFKA_RETRY_TOPICS", "notifications.retry.1m:1m,notifications.retry.10m:10m")
 	viper.SetDefault("KAFKA_DIAL_TIMEOUT", 10*time.Second)
 	viper.SetDefault("KAFKA_READ_TIMEOUT", 10*time.Second)
 	viper.SetDefault("KAFKA_MIN_BYTES", 10240)    // 10KB
 	viper.SetDefault("KAFKA_MAX_BYTES", 10485760) // 10MB
+	viper.SetDefault("KAFKA_START_OFFSET", "last")
+	viper.SetDefault("KAFKA_SCHEMAS_PATH", "assets/schemas/events")
+	viper.SetDefault("KAFKA_SECURITY_PROTOCOL", "PLAINTEXT")
+	viper.SetDefault("KAFKA_SASL_MECHANISM", "SCRAM-SHA-512")
 	viper.SetDefault("EMAIL_PROVIDER", "smtp")
 	viper.SetDefault("EMAIL_SMTP_PORT", 587)
 	viper.SetDefault("EMAIL_TEMPLATES_PATH", "assets/templates/email")
+	viper.SetDefault("EMAIL_APP_BASE_URL", "http://localhost:3000")
+	viper.SetDefault("AUTH_ADMIN_ROLE", "admin")
+	viper.SetDefault("RETRY_POLL_INTERVAL", 10*time.Second)
+	viper.SetDefault("RETRY_BATCH_SIZE", 50)
+	viper.SetDefault("RETRY_MAX_ATTEMPTS", 5)
+	viper.SetDefault("RETRY_BASE_DELAY", 30*time.Second)
+	viper.SetDefault("RETRY_MAX_DELAY", time.Hour)
+	viper.SetDefault("RETRY_JITTER", 0.2)
+	viper.SetDefault("RETRY_LOCK_TIMEOUT", 2*time.Minute)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", time.Second)
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 50)
+	viper.SetDefault("OUTBOX_LOCK_TIMEOUT", 2*time.Minute)
+	viper.SetDefault("SCHEDULER_POLL_INTERVAL", 5*time.Second)
+	viper.SetDefault("SCHEDULER_BATCH_SIZE", 100)
+	viper.SetDefault("DIGEST_POLL_INTERVAL", time.Minute)
+	viper.SetDefault("DIGEST_BATCH_SIZE", 50)
+	viper.SetDefault("DIGEST_RETRY_DELAY", 10*time.Minute)
+	viper.SetDefault("DIGEST_LOCK_TIMEOUT", 5*time.Minute)
+}
+
+// parseRetryTopics разбирает список ступеней вида "topic:delay,topic:delay", порядок важен
+func parseRetryTopics(raw string) ([]RetryTopicConfig, error) {
+	var tiers []RetryTopicConfig
+	seen := make(map[string]bool)
+
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		topic, rawDelay, found := strings.Cut(item, ":")
+		if !found || topic == "" {
+			return nil, fmt.Errorf("invalid KAFKA_RETRY_TOPICS entry %q, expected topic:delay", item)
+		}
+
+		delay, err := time.ParseDuration(rawDelay)
+		if err != nil || delay <= 0 {
+			return nil, fmt.Errorf("invalid delay in KAFKA_RETRY_TOPICS entry %q", item)
+		}
+
+		// ступень ищется по имени топика, повтор замкнул бы сообщение между ступенями
+		if seen[topic] {
+			return nil, fmt.Errorf("retry topic %s is listed more than once in KAFKA_RETRY_TOPICS", topic)
+		}
+		seen[topic] = true
+
+		tiers = append(tiers, RetryTopicConfig{Topic: topic, Delay: delay})
+	}
+
+	return tiers, nil
+}
+
+// parseReplay читает KAFKA_REPLAY_*: время в RFC3339, оффсеты вида "partition:start-end,..."
+// (end можно опустить: "0:100-")
+func parseReplay() (ReplayConfig, error) {
+	replay := ReplayConfig{Topic: viper.GetString("KAFKA_REPLAY_TOPIC")}
+
+	for key, target := range map[string]*time.Time{
+		"KAFKA_REPLAY_FROM":  &replay.FromTime,
+		"KAFKA_REPLAY_UNTIL": &replay.UntilTime,
+	} {
+		raw := viper.GetString(key)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return ReplayConfig{}, fmt.Errorf("invalid %s, expected RFC3339 time: %w", key, err)
+		}
+		*target = parsed
+	}
+
+	for _, item := range strings.Split(viper.GetString("KAFKA_REPLAY_OFFSETS"), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		rawPartition, rawRange, found := strings.Cut(item, ":")
+		rawStart, rawEnd, isRange := strings.Cut(rawRange, "-")
+		if !found || !isRange {
+			return ReplayConfig{}, fmt.Errorf("invalid KAFKA_REPLAY_OFFSETS entry %q, expected partition:start-end", item)
+		}
+
+		partition, err := strconv.Atoi(rawPartition)
+		if err != nil || partition < 0 {
+			return ReplayConfig{}, fmt.Errorf("invalid partition in KAFKA_REPLAY_OFFSETS entry %q", item)
+		}
+
+		offsets := OffsetRange{End: -1}
+		if offsets.Start, err = strconv.ParseInt(rawStart, 10, 64); err != nil || offsets.Start < 0 {
+			return ReplayConfig{}, fmt.Errorf("invalid start offset in KAFKA_REPLAY_OFFSETS entry %q", item)
+		}
+		if rawEnd != "" {
+			if offsets.End, err = strconv.ParseInt(rawEnd, 10, 64); err != nil || offsets.End < offsets.Start {
+				return ReplayConfig{}, fmt.Errorf("invalid end offset in KAFKA_REPLAY_OFFSETS entry %q", item)
+			}
+		}
+
+		if replay.Offsets == nil {
+			replay.Offsets = make(map[int]OffsetRange)
+		}
+		replay.Offsets[partition] = offsets
+	}
+
+	return replay, nil
 }
 
 func validateConfig(cfg *Config) error {
+	if cfg.Server.SSEHeartbeatInterval <= 0 {
+		return errors.New("SSE_HEARTBEAT_INTERVAL must be positive")
+	}
+
 	if cfg.Database.URL == "" {
 		return errors.New("DATABASE_URL is required")
 	}
@@ -120,6 +422,67 @@ func validateConfig(cfg *Config) error {
 		return errors.New("KAFKA_TOPIC_USER_EVENTS is required")
 	}
 
+	if cfg.Kafka.TopicOrderEvents == "" {
+		return errors.New("KAFKA_TOPIC_ORDER_EVENTS is required")
+	}
+
+	if cfg.Kafka.TopicChatEvents == "" {
+		return errors.New("KAFKA_TOPIC_CHAT_EVENTS is required")
+	}
+
+	if cfg.Kafka.TopicListingEvents == "" {
+		return errors.New("KAFKA_TOPIC_LISTING_EVENTS is required")
+	}
+
+	// у каждого топика свой обработчик, поэтому один топик не может обслуживать два источника
+	seenTopics := make(map[string]bool)
+	for _, topic := range []string{
+		cfg.Kafka.TopicUserEvents,
+		cfg.Kafka.TopicOrderEvents,
+		cfg.Kafka.TopicChatEvents,
+		cfg.Kafka.TopicListingEvents,
+	} {
+		if seenTopics[topic] {
+			return fmt.Errorf("kafka topic %s is configured for more than one event source", topic)
+		}
+		seenTopics[topic] = true
+	}
+
+	if cfg.Kafka.DLQTopic == "" {
+		return errors.New("KAFKA_DLQ_TOPIC is required")
+	}
+
+	if cfg.Kafka.OutcomeTopic == "" {
+		return errors.New("KAFKA_OUTCOME_TOPIC is required")
+	}
+
+	if cfg.Kafka.StartOffset != KafkaStartOffsetFirst && cfg.Kafka.StartOffset != KafkaStartOffsetLast {
+		return fmt.Errorf("KAFKA_START_OFFSET must be %s or %s", KafkaStartOffsetFirst, KafkaStartOffsetLast)
+	}
+
+	if err := validateReplay(&cfg.Kafka); err != nil {
+		return err
+	}
+
+	if cfg.Kafka.Workers < 1 {
+		return errors.New("KAFKA_WORKERS must be at least 1")
+	}
+
+	switch cfg.Kafka.SecurityProtocol {
+	case KafkaProtocolPlaintext, KafkaProtocolSSL:
+	case KafkaProtocolSASLPlaintext, KafkaProtocolSASLSSL:
+		switch cfg.Kafka.SASLMechanism {
+		case KafkaSASLPlain, KafkaSASLScramSHA256, KafkaSASLScramSHA512:
+		default:
+			return fmt.Errorf("KAFKA_SASL_MECHANISM %q is not supported", cfg.Kafka.SASLMechanism)
+		}
+		if cfg.Kafka.SASLUsername == "" || cfg.Kafka.SASLPassword == "" {
+			return errors.New("KAFKA_SASL_USERNAME and KAFKA_SASL_PASSWORD are required for SASL")
+		}
+	default:
+		return fmt.Errorf("KAFKA_SECURITY_PROTOCOL %q is not supported", cfg.Kafka.SecurityProtocol)
+	}
+
 	if cfg.Email.Provider == "smtp" {
 		if cfg.Email.SMTPHost == "" {
 			return errors.New("EMAIL_SMTP_HOST is required for SMTP provider")
@@ -136,5 +499,98 @@ func validateConfig(cfg *Config) error {
 		return errors.New("EMAIL_FROM_ADDRESS is required")
 	}
 
+	if cfg.Retry.MaxAttempts < 1 {
+		return errors.New("RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if cfg.Retry.Jitter < 0 || cfg.Retry.Jitter > 1 {
+		return errors.New("RETRY_JITTER must be between 0 and 1")
+	}
+
+	if cfg.Retry.PollInterval <= 0 {
+		return errors.New("RETRY_POLL_INTERVAL must be positive")
+	}
+
+	if cfg.Retry.BatchSize < 1 {
+		return errors.New("RETRY_BATCH_SIZE must be at least 1")
+	}
+
+	if cfg.Retry.LockTimeout <= 0 {
+		return errors.New("RETRY_LOCK_TIMEOUT must be positive")
+	}
+
+	if cfg.Outbox.PollInterval <= 0 {
+		return errors.New("OUTBOX_POLL_INTERVAL must be positive")
+	}
+
+	if cfg.Outbox.BatchSize < 1 {
+		return errors.New("OUTBOX_BATCH_SIZE must be at least 1")
+	}
+
+	if cfg.Outbox.LockTimeout <= 0 {
+		return errors.New("OUTBOX_LOCK_TIMEOUT must be positive")
+	}
+
+	if cfg.Scheduler.PollInterval <= 0 {
+		return errors.New("SCHEDULER_POLL_INTERVAL must be positive")
+	}
+
+	if cfg.Scheduler.BatchSize < 1 {
+		return errors.New("SCHEDULER_BATCH_SIZE must be at least 1")
+	}
+
+	if cfg.Digest.PollInterval <= 0 {
+		return errors.New("DIGEST_POLL_INTERVAL must be positive")
+	}
+
+	if cfg.Digest.BatchSize < 1 {
+		return errors.New("DIGEST_BATCH_SIZE must be at least 1")
+	}
+
+	if cfg.Digest.RetryDelay <= 0 {
+		return errors.New("DIGEST_RETRY_DELAY must be positive")
+	}
+
+	if cfg.Digest.LockTimeout <= 0 {
+		return errors.New("DIGEST_LOCK_TIMEOUT must be positive")
+	}
+
+	if cfg.Auth.JWTSecret == "" && cfg.Auth.JWKSPath == "" {
+		return errors.New("AUTH_JWT_SECRET or AUTH_JWKS_PATH is required")
+	}
+
+	return nil
+}
+
+func validateReplay(cfg *KafkaConfig) error {
+	replay := cfg.Replay
+	if !replay.Enabled() {
+		if !replay.FromTime.IsZero() || !replay.UntilTime.IsZero() || len(replay.Offsets) > 0 {
+			return errors.New("KAFKA_REPLAY_TOPIC is required for replay")
+		}
+		return nil
+	}
+
+	switch replay.Topic {
+	case cfg.TopicUserEvents, cfg.TopicOrderEvents, cfg.TopicChatEvents, cfg.TopicListingEvents:
+	default:
+		return fmt.Errorf("KAFKA_REPLAY_TOPIC %s is not one of the consumed topics", replay.Topic)
+	}
+
+	byTime := !replay.FromTime.IsZero()
+	byOffsets := len(replay.Offsets) > 0
+	if byTime == byOffsets {
+		return errors.New("exactly one of KAFKA_REPLAY_FROM or KAFKA_REPLAY_OFFSETS is required for replay")
+	}
+
+	if !replay.UntilTime.IsZero() {
+		if !byTime {
+			return errors.New("KAFKA_REPLAY_UNTIL can only be used with KAFKA_REPLAY_FROM")
+		}
+		if !replay.UntilTime.After(replay.FromTime) {
+			return errors.New("KAFKA_REPLAY_UNTIL must be after KAFKA_REPLAY_FROM")
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,343 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestParseReplay(t *testing.T) {
+	from := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    ReplayConfig
+		wantErr bool
+	}{
+		{
+			name: "disabled",
+			env:  map[string]string{},
+			want: ReplayConfig{},
+		},
+		{
+			name: "time window",
+			env: map[string]string{
+				"KAFKA_REPLAY_TOPIC": "order.events",
+				"KAFKA_REPLAY_FROM":  "2026-10-01T12:00:00Z",
+				"KAFKA_REPLAY_UNTIL": "2026-10-01T13:00:00Z",
+			},
+			want: ReplayConfig{
+				Topic:     "order.events",
+				FromTime:  from,
+				UntilTime: from.Add(time.Hour),
+			},
+		},
+		{
+			name: "offset ranges with open end",
+			env: map[string]string{
+				"KAFKA_REPLAY_TOPIC":   "order.events",
+				"KAFKA_REPLAY_OFFSETS": "0:100-200, 3:5-",
+			},
+			want: ReplayConfig{
+				Topic: "order.events",
+				Offsets: map[int]OffsetRange{
+					0: {Start: 100, End: 200},
+					3: {Start: 5, End: -1},
+				},
+			},
+		},
+		{
+			name:    "invalid time",
+			env:     map[string]string{"KAFKA_REPLAY_FROM": "yesterday"},
+			wantErr: true,
+		},
+		{
+			name:    "offset without range",
+			env:     map[string]string{"KAFKA_REPLAY_OFFSETS": "0:100"},
+			wantErr: true,
+		},
+		{
+			name:    "negative partition",
+			env:     map[string]string{"KAFKA_REPLAY_OFFSETS": "-1:0-10"},
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			env:     map[string]string{"KAFKA_REPLAY_OFFSETS": "0:10-5"},
+			wantErr: true,
+		},
+		{
+			name:    "non numeric start",
+			env:     map[string]string{"KAFKA_REPLAY_OFFSETS": "0:a-5"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			for key, value := range tt.env {
+				viper.Set(key, value)
+			}
+
+			got, err := parseReplay()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseReplay() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReplay() unexpected error: %v", err)
+			}
+
+			if got.Topic != tt.want.Topic || !got.FromTime.Equal(tt.want.FromTime) || !got.UntilTime.Equal(tt.want.UntilTime) {
+				t.Errorf("parseReplay() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.Offsets) != len(tt.want.Offsets) {
+				t.Fatalf("parseReplay() offsets = %v, want %v", got.Offsets, tt.want.Offsets)
+			}
+			for partition, offsets := range tt.want.Offsets {
+				if got.Offsets[partition] != offsets {
+					t.Errorf("partition %d offsets = %+v, want %+v", partition, got.Offsets[partition], offsets)
+				}
+			}
+		})
+	}
+}
+
+func validConfig() *Config {
+	return &Config{
+		Server: ServerConfig{SSEHeartbeatInterval: 15 * time.Second},
+		Database: DatabaseConfig{
+			URL: "postgres://localhost/notifications",
+		},
+		Kafka: KafkaConfig{
+			Brokers:            []string{"localhost:9092"},
+			TopicUserEvents:    "user.events",
+			TopicOrderEvents:   "order.events",
+			TopicChatEvents:    "chat.events",
+			TopicListingEvents: "listing.events",
+			DLQTopic:           "notifications.dlq",
+			OutcomeTopic:       "notification.events",
+			StartOffset:        KafkaStartOffsetLast,
+			Workers:            8,
+			SecurityProtocol:   KafkaProtocolPlaintext,
+		},
+		Email: EmailConfig{
+			Provider:     "smtp",
+			SMTPHost:     "smtp.example.com",
+			SMTPUsername: "user",
+			SMTPPassword: "secret",
+			FromAddress:  "noreply@example.com",
+		},
+		Auth: AuthConfig{JWTSecret: "secret"},
+		Retry: RetryConfig{
+			PollInterval: 10 * time.Second,
+			BatchSize:    50,
+			MaxAttempts:  5,
+			BaseDelay:    30 * time.Second,
+			MaxDelay:     time.Hour,
+			Jitter:       0.2,
+			LockTimeout:  2 * time.Minute,
+		},
+		Outbox: OutboxConfig{
+			PollInterval: time.Second,
+			BatchSize:    50,
+			LockTimeout:  2 * time.Minute,
+		},
+		Scheduler: SchedulerConfig{
+			PollInterval: 5 * time.Second,
+			BatchSize:    100,
+		},
+		Digest: DigestConfig{
+			PollInterval: time.Minute,
+			BatchSize:    50,
+			RetryDelay:   10 * time.Minute,
+			LockTimeout:  5 * time.Minute,
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *Config)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(cfg *Config) {},
+		},
+		{
+			name:    "zero sse heartbeat interval",
+			mutate:  func(cfg *Config) { cfg.Server.SSEHeartbeatInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero retry poll interval",
+			mutate:  func(cfg *Config) { cfg.Retry.PollInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative retry poll interval",
+			mutate:  func(cfg *Config) { cfg.Retry.PollInterval = -time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "zero retry batch size",
+			mutate:  func(cfg *Config) { cfg.Retry.BatchSize = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero retry lock timeout",
+			mutate:  func(cfg *Config) { cfg.Retry.LockTimeout = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero outbox poll interval",
+			mutate:  func(cfg *Config) { cfg.Outbox.PollInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative outbox batch size",
+			mutate:  func(cfg *Config) { cfg.Outbox.BatchSize = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "zero outbox lock timeout",
+			mutate:  func(cfg *Config) { cfg.Outbox.LockTimeout = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero scheduler poll interval",
+			mutate:  func(cfg *Config) { cfg.Scheduler.PollInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero scheduler batch size",
+			mutate:  func(cfg *Config) { cfg.Scheduler.BatchSize = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero digest poll interval",
+			mutate:  func(cfg *Config) { cfg.Digest.PollInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero digest batch size",
+			mutate:  func(cfg *Config) { cfg.Digest.BatchSize = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative digest retry delay",
+			mutate:  func(cfg *Config) { cfg.Digest.RetryDelay = -time.Minute },
+			wantErr: true,
+		},
+		{
+			name:    "zero digest lock timeout",
+			mutate:  func(cfg *Config) { cfg.Digest.LockTimeout = 0 },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(cfg)
+
+			err := validateConfig(cfg)
+			if tt.wantErr && err == nil {
+				t.Fatalf("validateConfig() error = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("validateConfig() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRetryTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []RetryTopicConfig
+		wantErr bool
+	}{
+		{
+			name: "two tiers",
+			raw:  "notifications.retry.1m:1m, notifications.retry.10m:10m",
+			want: []RetryTopicConfig{
+				{Topic: "notifications.retry.1m", Delay: time.Minute},
+				{Topic: "notifications.retry.10m", Delay: 10 * time.Minute},
+			},
+		},
+		{
+			name: "empty disables retry topics",
+			raw:  "",
+		},
+		{
+			name: "trailing comma",
+			raw:  "notifications.retry.1m:1m,",
+			want: []RetryTopicConfig{{Topic: "notifications.retry.1m", Delay: time.Minute}},
+		},
+		{name: "missing delay", raw: "notifications.retry.1m", wantErr: true},
+		{name: "missing topic", raw: ":1m", wantErr: true},
+		{name: "invalid delay", raw: "notifications.retry.1m:soon", wantErr: true},
+		{name: "zero delay", raw: "notifications.retry.1m:0s", wantErr: true},
+		{name: "negative delay", raw: "notifications.retry.1m:-1m", wantErr: true},
+		{name: "duplicate topic", raw: "notifications.retry:1m,notifications.retry:10m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRetryTopics(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseRetryTopics(%q) = %v, want error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRetryTopics(%q) unexpected error: %v", tt.raw, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseRetryTopics(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	t.Setenv("DATABASE_URL", "postgres://localhost:5432/notifications")
+	t.Setenv("KAFKA_BROKERS", "localhost:9092")
+	t.Setenv("KAFKA_TOPIC_USER_EVENTS", "user.events")
+	t.Setenv("AUTH_JWT_SECRET", "secret")
+	t.Setenv("EMAIL_SMTP_HOST", "smtp.avigo.ru")
+	t.Setenv("EMAIL_SMTP_USERNAME", "notifications")
+	t.Setenv("EMAIL_SMTP_PASSWORD", "password")
+	t.Setenv("EMAIL_FROM_ADDRESS", "noreply@avigo.ru")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error: %v", err)
+	}
+
+	topics := []string{
+		cfg.Kafka.TopicUserEvents,
+		cfg.Kafka.TopicOrderEvents,
+		cfg.Kafka.TopicChatEvents,
+		cfg.Kafka.TopicListingEvents,
+	}
+	want := []string{"user.events", "order.events", "chat.events", "listing.events"}
+	if !slices.Equal(topics, want) {
+		t.Errorf("LoadConfig() topics = %v, want %v", topics, want)
+	}
+}
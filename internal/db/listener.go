@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+// NotificationChangesChannel - канал pg_notify, в который пишет триггер notify_notifications_change
+const NotificationChangesChannel = "notification_changes"
+
+const (
+	listenerMinBackoff = time.Second
+	listenerMaxBackoff = 30 * time.Second
+)
+
+type NotificationChange struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Operation string    `json:"operation"` // INSERT | UPDATE
+}
+
+type NotificationChangeHandler func(ctx context.Context, change NotificationChange)
+
+// NotificationListener держит отдельное соединение с LISTEN notification_changes.
+// Каждая реплика получает изменения всех реплик и может отдать их своим подключенным пользователям
+type NotificationListener struct {
+	url     string
+	handler NotificationChangeHandler
+}
+
+func NewNotificationListener(cfg *config.DatabaseConfig, handler NotificationChangeHandler) *NotificationListener {
+	return &NotificationListener{
+		url:     cfg.URL,
+		handler: handler,
+	}
+}
+
+// Start слушает канал до отмены контекста, при обрыве соединения переподключается с backoff
+func (l *NotificationListener) Start(ctx context.Context) error {
+	backoff := listenerMinBackoff
+
+	for {
+		connected, err := l.listen(ctx)
+		if ctx.Err() != nil {
+			log.Println("Context cancelled, stopping notification listener...")
+			return nil
+		}
+
+		if connected {
+			backoff = listenerMinBackoff
+		}
+
+		log.Printf("Notification listener disconnected: %v, reconnecting in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenerMaxBackoff)
+	}
+}
+
+func (l *NotificationListener) listen(ctx context.Context) (bool, error) {
+	conn, err := pgx.Connect(ctx, l.url)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{NotificationChangesChannel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("failed to listen %s: %w", NotificationChangesChannel, err)
+	}
+	log.Printf("Listening PostgreSQL channel %s", NotificationChangesChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("failed to wait for notification: %w", err)
+		}
+
+		var change NotificationChange
+		if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+			log.Printf("Invalid %s payload %q: %v", NotificationChangesChannel, notification.Payload, err)
+			continue
+		}
+
+		l.handler(ctx, change)
+	}
+}
@@ -20,6 +20,8 @@ func NewPostgresDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 			return time.Now().UTC()
 		},
 		DisableForeignKeyConstraintWhenMigrating: true,
+		// нарушение уникальности возвращается как gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 
 	if err != nil {
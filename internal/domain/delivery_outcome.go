@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutcomeType - итог доставки уведомления, о котором сообщается другим сервисам
+type OutcomeType string
+
+const (
+	OutcomeSent   OutcomeType = "notification.sent"
+	OutcomeFailed OutcomeType = "notification.failed"
+	OutcomeRead   OutcomeType = "notification.read"
+)
+
+type DeliveryOutcome struct {
+	// ID не меняется при повторной публикации, по нему получатели отбрасывают дубли
+	ID               uuid.UUID
+	Type             OutcomeType
+	NotificationID   uuid.UUID
+	UserID           uuid.UUID
+	NotificationType NotificationType
+	// SourceEventID - event_id исходного события, пусто для уведомлений не из событий
+	SourceEventID string
+	// Channel - канал доставки (in_app для уведомлений без внешних каналов), пусто для notification.read
+	Channel    DeliveryChannel
+	Reason     string
+	OccurredAt time.Time
+}
+
+// NewDeliveryOutcome собирает событие об итоге из задачи outbox и уведомления
+func NewDeliveryOutcome(message *OutboxMessage, notification *Notification) DeliveryOutcome {
+	outcome := DeliveryOutcome{
+		ID:               message.Id,
+		Type:             message.Outcome,
+		NotificationID:   notification.Id,
+		UserID:           notification.UserID,
+		NotificationType: notification.Type,
+		Channel:          message.Channel,
+		OccurredAt:       message.CreatedAt,
+	}
+	if notification.EventID != nil {
+		outcome.SourceEventID = *notification.EventID
+	}
+	if message.Outcome == OutcomeFailed {
+		outcome.Reason = notification.LastError
+	}
+	return outcome
+}
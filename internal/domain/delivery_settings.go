@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultTimezone = "UTC"
+
+// DeliverySettings - общие для всех типов уведомлений настройки доставки пользователя.
+// Тихие часы задаются в минутах от начала суток по часовому поясу пользователя
+// и могут переходить через полночь (например, 22:00-08:00)
+type DeliverySettings struct {
+	UserID          uuid.UUID `gorm:"primaryKey"`
+	Timezone        string
+	QuietHoursStart *int
+	QuietHoursEnd   *int
+	DigestMode      DigestMode
+	UpdatedAt       time.Time
+}
+
+func (*DeliverySettings) TableName() string {
+	return "notification_settings"
+}
+
+// DefaultDeliverySettings - настройки пользователя, который ничего не менял: без тихих часов и сводок
+func DefaultDeliverySettings(userID uuid.UUID) *DeliverySettings {
+	return &DeliverySettings{
+		UserID:     userID,
+		Timezone:   DefaultTimezone,
+		DigestMode: DigestInstant,
+	}
+}
+
+func (s *DeliverySettings) HasQuietHours() bool {
+	return s.QuietHoursStart != nil && s.QuietHoursEnd != nil && *s.QuietHoursStart != *s.QuietHoursEnd
+}
+
+// Location возвращает часовой пояс пользователя, некорректный пояс в базе не должен ломать доставку
+func (s *DeliverySettings) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// QuietUntil возвращает момент окончания тихих часов, если now попадает в них
+func (s *DeliverySettings) QuietUntil(now time.Time) (time.Time, bool) {
+	if !s.HasQuietHours() {
+		return time.Time{}, false
+	}
+
+	local := now.In(s.Location())
+	minute := local.Hour()*60 + local.Minute()
+	start, end := *s.QuietHoursStart, *s.QuietHoursEnd
+
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !until.After(local) {
+		until = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, local.Location())
+	}
+
+	return until.UTC(), true
+}
+
+// ParseClock разбирает время суток в формате HH:MM в минуты от начала суток
+func ParseClock(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidClock, value)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// DigestMode - как пользователь получает письма о частых уведомлениях:
+// сразу или одной сводкой раз в час / раз в день
+type DigestMode string
+
+const (
+	DigestInstant DigestMode = "instant"
+	DigestHourly  DigestMode = "hourly"
+	DigestDaily   DigestMode = "daily"
+)
+
+// DailyDigestHour - час отправки ежедневной сводки по часовому поясу пользователя
+const DailyDigestHour = 9
+
+// в сводку попадают только частые и не срочные уведомления, письма о заказах приходят сразу
+var digestibleTypes = []NotificationType{
+	TypeNewMessage,
+	TypeListingUpdate,
+	TypeNewReview,
+}
+
+func (m DigestMode) IsValid() bool {
+	switch m {
+	case DigestInstant, DigestHourly, DigestDaily:
+		return true
+	}
+	return false
+}
+
+func (t NotificationType) IsDigestible() bool {
+	return slices.Contains(digestibleTypes, t)
+}
+
+// Batches - уведомления этого типа при выбранном режиме копятся для сводки вместо отдельного письма
+func (s *DeliverySettings) Batches(notificationType NotificationType) bool {
+	return s.DigestMode != "" && s.DigestMode != DigestInstant && notificationType.IsDigestible()
+}
+
+// NextDigestAt возвращает ближайшее после at время отправки сводки:
+// начало следующего часа или DailyDigestHour следующего подходящего дня
+func (s *DeliverySettings) NextDigestAt(at time.Time) time.Time {
+	local := at.In(s.Location())
+
+	var next time.Time
+	switch s.DigestMode {
+	case DigestDaily:
+		next = time.Date(local.Year(), local.Month(), local.Day(), DailyDigestHour, 0, 0, 0, local.Location())
+		if !next.After(local) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+1, DailyDigestHour, 0, 0, 0, local.Location())
+		}
+	default:
+		// Truncate округляет абсолютное время, а не местное: в поясах со смещением
+		// вроде +05:30 это давало бы середину часа
+		next = time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, local.Location())
+	}
+
+	return next.UTC()
+}
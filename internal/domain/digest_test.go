@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestNextDigestAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     DigestMode
+		timezone string
+		at       string
+		want     string
+	}{
+		{
+			name:     "hourly in whole hour offset",
+			mode:     DigestHourly,
+			timezone: "Europe/Moscow",
+			at:       "2026-10-18T10:20:00Z",
+			want:     "2026-10-18T11:00:00Z",
+		},
+		{
+			name:     "hourly at the top of the hour moves to the next one",
+			mode:     DigestHourly,
+			timezone: "Europe/Moscow",
+			at:       "2026-10-18T10:00:00Z",
+			want:     "2026-10-18T11:00:00Z",
+		},
+		{
+			name:     "hourly in half hour offset",
+			mode:     DigestHourly,
+			timezone: "Asia/Kolkata",
+			at:       "2026-10-18T10:20:00Z", // 15:50 по местному времени
+			want:     "2026-10-18T10:30:00Z", // 16:00
+		},
+		{
+			name:     "hourly in quarter hour offset",
+			mode:     DigestHourly,
+			timezone: "Asia/Kathmandu",
+			at:       "2026-10-18T10:20:00Z", // 16:05 по местному времени
+			want:     "2026-10-18T11:15:00Z", // 17:00
+		},
+		{
+			name:     "daily before digest hour",
+			mode:     DigestDaily,
+			timezone: "Europe/Moscow",
+			at:       "2026-10-18T04:00:00Z", // 07:00
+			want:     "2026-10-18T06:00:00Z", // 09:00
+		},
+		{
+			name:     "daily after digest hour",
+			mode:     DigestDaily,
+			timezone: "Asia/Kolkata",
+			at:       "2026-10-18T04:00:00Z", // 09:30
+			want:     "2026-10-19T03:30:00Z", // 09:00 следующего дня
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &DeliverySettings{Timezone: tt.timezone, DigestMode: tt.mode}
+			at, _ := time.Parse(time.RFC3339, tt.at)
+			want, _ := time.Parse(time.RFC3339, tt.want)
+
+			if got := settings.NextDigestAt(at); !got.Equal(want) {
+				t.Errorf("NextDigestAt(%s) = %s, want %s", tt.at, got, want)
+			}
+		})
+	}
+}
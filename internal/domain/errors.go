@@ -8,4 +8,41 @@ var (
 	ErrMissingConfirmationCode = errors.New("confirmation_code is required")
 	ErrExpiredCode             = errors.New("confirmation code has expired")
 	ErrInvalidUUID             = errors.New("invalid UUID format")
+	ErrMissingEventID          = errors.New("event_id is required")
+	ErrMissingEventType        = errors.New("event_type is required")
+	ErrMissingSchemaVersion    = errors.New("schema_version must be at least 1")
+	ErrDuplicateEvent          = errors.New("event has already been processed")
+	ErrMissingChatID           = errors.New("chat_id is required")
+	ErrMissingSenderID         = errors.New("sender_id is required")
+	ErrMissingListingID        = errors.New("listing_id is required")
+	ErrUnknownListingChange    = errors.New("unknown listing change kind")
+	ErrUnknownListingStatus    = errors.New("unknown listing status")
+	ErrMissingReviewID         = errors.New("review_id is required")
+	ErrInvalidRating           = errors.New("rating must be between 1 and 5")
+	ErrMissingOrderID          = errors.New("order_id is required")
+	ErrMissingBuyerID          = errors.New("buyer_id is required")
+	ErrMissingSellerID         = errors.New("seller_id is required")
+	ErrBuyerIsSeller           = errors.New("buyer and seller must be different users")
+	ErrUnknownOrderStatus      = errors.New("unknown order status")
+	ErrMissingTargetEventID    = errors.New("target_event_id is required")
+
+	ErrNotificationNotFound  = errors.New("notification not found")
+	ErrInvalidNotificationID = errors.New("invalid notification ID")
+
+	ErrInvalidStatusTransition = errors.New("invalid notification status transition")
+	ErrDeliveryInterrupted     = errors.New("delivery was interrupted before completion")
+	ErrChannelDisabled         = errors.New("delivery channel is disabled by user preferences")
+
+	ErrUnknownNotificationType = errors.New("unknown notification type")
+	ErrUnknownDeliveryChannel  = errors.New("unknown delivery channel")
+	ErrMandatoryNotification   = errors.New("mandatory notification type cannot be disabled")
+	ErrDuplicatePreference     = errors.New("preference for the same type and channel is set more than once")
+	ErrEmptyPreferences        = errors.New("preferences are required")
+	ErrInvalidTimezone         = errors.New("invalid timezone")
+	ErrInvalidClock            = errors.New("time of day must be in HH:MM format")
+	ErrInvalidQuietHours       = errors.New("quiet hours start and end must differ")
+	ErrUnknownDigestMode       = errors.New("digest mode must be instant, hourly or daily")
+
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("access to another user's notifications is forbidden")
 )
@@ -0,0 +1,18 @@
+package domain
+
+// ListingChangeKind - что именно изменилось в объявлении
+type ListingChangeKind string
+
+const (
+	ListingPriceChanged       ListingChangeKind = "price_changed"
+	ListingStatusChanged      ListingChangeKind = "status_changed"
+	ListingModerationRejected ListingChangeKind = "moderation_rejected"
+)
+
+type ListingStatus string
+
+const (
+	ListingStatusSold      ListingStatus = "sold"
+	ListingStatusArchived  ListingStatus = "archived"
+	ListingStatusModerated ListingStatus = "moderated"
+)
@@ -7,16 +7,36 @@ import (
 )
 
 type Notification struct {
-	Id     uuid.UUID
+	Id     uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
 	UserID uuid.UUID
 	Type   NotificationType
 
+	// EventID - ID исходного Kafka события, nil для уведомлений, созданных не из событий
+	EventID *string
+
 	Title   string
 	Message string
 
 	Metadata JSONB
 	Status   NotificationStatus
 
+	// SendAt - время отложенной доставки, до него уведомление не видно пользователю и не отправляется
+	SendAt *time.Time
+
+	// DigestAt - время сводки, в которую попадет письмо об уведомлении вместо отдельной отправки.
+	// До отправки сводки уведомление не получает финальный статус, DigestedAt - время отправки сводки
+	DigestAt   *time.Time
+	DigestedAt *time.Time
+
+	AttemptCount  int
+	LastError     string
+	NextAttemptAt *time.Time
+	// LockedUntil - аренда попытки, взятой retry воркером. Если воркер упал посреди отправки,
+	// после истечения аренды уведомление забирается снова
+	LockedUntil *time.Time
+	SentAt      *time.Time
+	FailedAt    *time.Time
+
 	ReadAt    *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -35,6 +55,7 @@ const (
 	TypeNewMessage        NotificationType = "new_message"
 	TypeReviewCreated     NotificationType = "review_created"
 	TypeNewReview         NotificationType = "new_review"
+	TypeListingUpdate     NotificationType = "listing_update"
 )
 
 type DeliveryChannel string
@@ -42,12 +63,20 @@ type DeliveryChannel string
 const (
 	ChannelEmail DeliveryChannel = "email"
 	ChannelPush  DeliveryChannel = "push"
+	// ChannelInApp - только входящие в приложении, отдельной доставки нет и в настройках не отключается
+	ChannelInApp DeliveryChannel = "in_app"
 )
 
 type NotificationStatus string
 
 const (
-	StatusPending NotificationStatus = "pending"
-	StatusSent    NotificationStatus = "sent"
-	StatusFailed  NotificationStatus = "failed"
+	StatusPending   NotificationStatus = "pending"
+	StatusSending   NotificationStatus = "sending"
+	StatusSent      NotificationStatus = "sent"
+	StatusFailed    NotificationStatus = "failed"
+	StatusRetrying  NotificationStatus = "retrying"
+	StatusCancelled NotificationStatus = "cancelled"
+	StatusScheduled NotificationStatus = "scheduled"
+	// StatusSkipped - канал отключили, пока уведомление ждало доставки. Во входящих оно остается
+	StatusSkipped NotificationStatus = "skipped"
 )
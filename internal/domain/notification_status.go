@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Жизненный цикл уведомления:
+//
+//	scheduled -> pending, sent (только in-app)
+//	pending -> sending -> sent
+//	              |  -> failed
+//	              |  -> retrying -> sending
+//	              |  -> skipped
+//	scheduled, pending, retrying -> cancelled
+//
+// sent, skipped и cancelled - финальные статусы. failed без next_attempt_at тоже не обрабатывается,
+// но оператор может вернуть уведомление в работу, выставив next_attempt_at вручную
+var statusTransitions = map[NotificationStatus][]NotificationStatus{
+	StatusScheduled: {StatusPending, StatusSent, StatusCancelled},
+	StatusPending:   {StatusSending, StatusCancelled},
+	StatusSending:   {StatusSent, StatusFailed, StatusRetrying, StatusSkipped},
+	StatusRetrying:  {StatusSending, StatusCancelled},
+	StatusFailed:    {StatusSending},
+}
+
+func CanTransition(from, to NotificationStatus) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notification) TransitionTo(to NotificationStatus) error {
+	if !CanTransition(n.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, n.Status, to)
+	}
+	n.Status = to
+	return nil
+}
+
+// MarkSending начинает очередную попытку доставки
+func (n *Notification) MarkSending() error {
+	if err := n.TransitionTo(StatusSending); err != nil {
+		return err
+	}
+	n.AttemptCount++
+	n.NextAttemptAt = nil
+	return nil
+}
+
+func (n *Notification) MarkSent(at time.Time) error {
+	if err := n.TransitionTo(StatusSent); err != nil {
+		return err
+	}
+	n.SentAt = &at
+	n.LastError = ""
+	n.LockedUntil = nil
+	return nil
+}
+
+// MarkFailed - окончательная неудача, повторных попыток не будет
+func (n *Notification) MarkFailed(cause error, at time.Time) error {
+	if err := n.TransitionTo(StatusFailed); err != nil {
+		return err
+	}
+	n.FailedAt = &at
+	n.LastError = errorText(cause)
+	n.NextAttemptAt = nil
+	n.LockedUntil = nil
+	return nil
+}
+
+// MarkSkipped - пользователь отключил канал уже после постановки уведомления в очередь.
+// Это не ошибка доставки, поэтому last_error и failed_at не заполняются
+func (n *Notification) MarkSkipped() error {
+	if err := n.TransitionTo(StatusSkipped); err != nil {
+		return err
+	}
+	n.NextAttemptAt = nil
+	n.LockedUntil = nil
+	return nil
+}
+
+// MarkRetrying - попытка неудачна, доставка будет повторена не раньше nextAttemptAt
+func (n *Notification) MarkRetrying(cause error, nextAttemptAt time.Time) error {
+	if err := n.TransitionTo(StatusRetrying); err != nil {
+		return err
+	}
+	n.LastError = errorText(cause)
+	n.NextAttemptAt = &nextAttemptAt
+	n.LockedUntil = nil
+	return nil
+}
+
+// IsScheduledAfter - доставка уведомления запланирована на время позже now
+func (n *Notification) IsScheduledAfter(now time.Time) bool {
+	return n.SendAt != nil && n.SendAt.After(now)
+}
+
+// Release выпускает отложенное уведомление: оно становится видно пользователю,
+// а при наличии внешних каналов передается в обычную доставку через outbox
+func (n *Notification) Release(at time.Time, hasChannels bool) error {
+	if !hasChannels {
+		if err := n.TransitionTo(StatusSent); err != nil {
+			return err
+		}
+		n.SentAt = &at
+		return nil
+	}
+	return n.TransitionTo(StatusPending)
+}
+
+// IsVisible - отложенное уведомление не показывается во входящих до выпуска,
+// в том числе если его отменили раньше
+func (n *Notification) IsVisible() bool {
+	if n.Status == StatusScheduled {
+		return false
+	}
+	return n.Status != StatusCancelled || n.SendAt == nil
+}
+
+func (n *Notification) Cancel() error {
+	return n.TransitionTo(StatusCancelled)
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to NotificationStatus
+		want     bool
+	}{
+		{StatusScheduled, StatusPending, true},
+		{StatusScheduled, StatusSent, true},
+		{StatusScheduled, StatusCancelled, true},
+		{StatusScheduled, StatusSending, false},
+		{StatusPending, StatusSending, true},
+		{StatusPending, StatusCancelled, true},
+		{StatusPending, StatusSent, false},
+		{StatusSending, StatusSent, true},
+		{StatusSending, StatusFailed, true},
+		{StatusSending, StatusRetrying, true},
+		{StatusSending, StatusSkipped, true},
+		{StatusSending, StatusCancelled, false},
+		{StatusPending, StatusSkipped, false},
+		{StatusSkipped, StatusSending, false},
+		{StatusRetrying, StatusSending, true},
+		{StatusRetrying, StatusCancelled, true},
+		{StatusRetrying, StatusSent, false},
+		{StatusFailed, StatusSending, true},
+		{StatusFailed, StatusCancelled, false},
+		{StatusSent, StatusSending, false},
+		{StatusSent, StatusFailed, false},
+		{StatusCancelled, StatusPending, false},
+		{StatusCancelled, StatusSending, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestNotificationMarkTransitions(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	lease := now.Add(time.Minute)
+	next := now.Add(time.Hour)
+	cause := errors.New("smtp unavailable")
+
+	tests := []struct {
+		name    string
+		from    NotificationStatus
+		mark    func(n *Notification) error
+		wantErr bool
+		check   func(t *testing.T, n *Notification)
+	}{
+		{
+			name: "sending counts attempt and clears next attempt",
+			from: StatusPending,
+			mark: (*Notification).MarkSending,
+			check: func(t *testing.T, n *Notification) {
+				if n.Status != StatusSending || n.AttemptCount != 2 || n.NextAttemptAt != nil {
+					t.Errorf("got status %s, attempts %d, next %v", n.Status, n.AttemptCount, n.NextAttemptAt)
+				}
+			},
+		},
+		{
+			name: "sent clears error and lease",
+			from: StatusSending,
+			mark: func(n *Notification) error { return n.MarkSent(now) },
+			check: func(t *testing.T, n *Notification) {
+				if n.Status != StatusSent || n.SentAt == nil || !n.SentAt.Equal(now) {
+					t.Errorf("got status %s, sent at %v", n.Status, n.SentAt)
+				}
+				if n.LastError != "" || n.LockedUntil != nil {
+					t.Errorf("got last error %q, locked until %v", n.LastError, n.LockedUntil)
+				}
+			},
+		},
+		{
+			name: "failed records cause",
+			from: StatusSending,
+			mark: func(n *Notification) error { return n.MarkFailed(cause, now) },
+			check: func(t *testing.T, n *Notification) {
+				if n.Status != StatusFailed || n.FailedAt == nil || n.LastError != cause.Error() {
+					t.Errorf("got status %s, failed at %v, last error %q", n.Status, n.FailedAt, n.LastError)
+				}
+				if n.NextAttemptAt != nil || n.LockedUntil != nil {
+					t.Errorf("got next %v, locked until %v", n.NextAttemptAt, n.LockedUntil)
+				}
+			},
+		},
+		{
+			name: "retrying schedules next attempt",
+			from: StatusSending,
+			mark: func(n *Notification) error { return n.MarkRetrying(cause, next) },
+			check: func(t *testing.T, n *Notification) {
+				if n.Status != StatusRetrying || n.NextAttemptAt == nil || !n.NextAttemptAt.Equal(next) {
+					t.Errorf("got status %s, next %v", n.Status, n.NextAttemptAt)
+				}
+				if n.LastError != cause.Error() || n.LockedUntil != nil {
+					t.Errorf("got last error %q, locked until %v", n.LastError, n.LockedUntil)
+				}
+			},
+		},
+		{
+			name: "skipped clears lease without recording an error",
+			from: StatusSending,
+			mark: (*Notification).MarkSkipped,
+			check: func(t *testing.T, n *Notification) {
+				if n.Status != StatusSkipped || n.LockedUntil != nil {
+					t.Errorf("got status %s, locked until %v", n.Status, n.LockedUntil)
+				}
+				if n.LastError != "" || n.FailedAt != nil {
+					t.Errorf("got last error %q, failed at %v", n.LastError, n.FailedAt)
+				}
+			},
+		},
+		{
+			name:    "sent notification cannot be sent again",
+			from:    StatusSent,
+			mark:    (*Notification).MarkSending,
+			wantErr: true,
+		},
+		{
+			name:    "pending notification cannot fail without attempt",
+			from:    StatusPending,
+			mark:    func(n *Notification) error { return n.MarkFailed(cause, now) },
+			wantErr: true,
+		},
+		{
+			name:    "cancelled notification cannot be retried",
+			from:    StatusCancelled,
+			mark:    func(n *Notification) error { return n.MarkRetrying(cause, next) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Notification{Status: tt.from, AttemptCount: 1, LockedUntil: &lease}
+
+			err := tt.mark(n)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidStatusTransition) {
+					t.Fatalf("error = %v, want ErrInvalidStatusTransition", err)
+				}
+				if n.Status != tt.from || n.AttemptCount != 1 {
+					t.Errorf("rejected transition changed notification: status %s, attempts %d", n.Status, n.AttemptCount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, n)
+		})
+	}
+}
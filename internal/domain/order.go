@@ -0,0 +1,20 @@
+package domain
+
+// OrderStatus - статус заказа, о переходе в который уведомляются покупатель и продавец
+type OrderStatus string
+
+const (
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusDisputed  OrderStatus = "disputed"
+)
+
+// OrderRole - роль получателя уведомления в заказе, от нее зависит формулировка
+type OrderRole string
+
+const (
+	OrderRoleBuyer  OrderRole = "buyer"
+	OrderRoleSeller OrderRole = "seller"
+)
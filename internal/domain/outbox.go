@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage - задача доставки уведомления по одному каналу или публикации итога доставки.
+// Создается в той же транзакции, что и уведомление (или смена его статуса), поэтому ни уведомление,
+// ни событие об итоге не могут потеряться между сохранением и отправкой
+type OutboxMessage struct {
+	Id             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	NotificationID uuid.UUID
+	Channel        DeliveryChannel
+	// Outcome - итог доставки для публикации, пусто у задач доставки по каналам
+	Outcome OutcomeType
+
+	AvailableAt time.Time
+	LockedUntil *time.Time
+	Attempts    int
+	LastError   string
+	ProcessedAt *time.Time
+
+	CreatedAt time.Time
+}
+
+func (*OutboxMessage) TableName() string {
+	return "notification_outbox"
+}
+
+// NewOutcomeMessage - задача публикации итога доставки, channel пуст для notification.read
+func NewOutcomeMessage(outcome OutcomeType, notificationID uuid.UUID, channel DeliveryChannel, at time.Time) *OutboxMessage {
+	return &OutboxMessage{
+		NotificationID: notificationID,
+		Channel:        channel,
+		Outcome:        outcome,
+		AvailableAt:    at,
+		CreatedAt:      at,
+	}
+}
+
+func (m *OutboxMessage) IsOutcome() bool {
+	return m.Outcome != ""
+}
+
+// PendingDelivery - новое уведомление и каналы, по которым его нужно доставить
+type PendingDelivery struct {
+	Notification *Notification
+	Channels     []DeliveryChannel
+
+	// DeliverAfter откладывает доставку по каналам (например, до конца тихих часов),
+	// нулевое значение - доставить сразу
+	DeliverAfter time.Time
+}
+
+// NewPendingDelivery выставляет начальный статус уведомления: отложенное ждет send_at,
+// без внешних каналов и сводки уведомление только in-app и считается доставленным сразу после сохранения
+func NewPendingDelivery(notification *Notification, channels ...DeliveryChannel) PendingDelivery {
+	delivery := PendingDelivery{
+		Notification: notification,
+		Channels:     channels,
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case notification.IsScheduledAfter(now):
+		notification.Status = StatusScheduled
+		delivery.DeliverAfter = *notification.SendAt
+	case len(channels) == 0 && notification.DigestAt == nil:
+		notification.Status = StatusSent
+		notification.SentAt = &now
+	default:
+		notification.Status = StatusPending
+	}
+
+	return delivery
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreference - отписка (или обратная подписка) пользователя от типа уведомлений в одном канале.
+// Отсутствие записи означает, что канал включен
+type NotificationPreference struct {
+	UserID    uuid.UUID        `gorm:"primaryKey"`
+	Type      NotificationType `gorm:"primaryKey"`
+	Channel   DeliveryChannel  `gorm:"primaryKey"`
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+func (*NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+var (
+	NotificationTypes = []NotificationType{
+		TypeEmailVerification,
+		TypeOrderCreated,
+		TypeOrderStatusChange,
+		TypeNewMessage,
+		TypeReviewCreated,
+		TypeNewReview,
+		TypeListingUpdate,
+	}
+
+	DeliveryChannels = []DeliveryChannel{
+		ChannelEmail,
+		ChannelPush,
+	}
+
+	// обязательные уведомления доставляются всегда, отключить их нельзя
+	mandatoryTypes = []NotificationType{
+		TypeEmailVerification,
+	}
+
+	// срочные уведомления (подтверждения, безопасность) не откладываются на тихие часы
+	urgentTypes = []NotificationType{
+		TypeEmailVerification,
+	}
+)
+
+func (t NotificationType) IsValid() bool {
+	return slices.Contains(NotificationTypes, t)
+}
+
+func (t NotificationType) IsMandatory() bool {
+	return slices.Contains(mandatoryTypes, t)
+}
+
+func (t NotificationType) IsUrgent() bool {
+	return slices.Contains(urgentTypes, t)
+}
+
+func (c DeliveryChannel) IsValid() bool {
+	return slices.Contains(DeliveryChannels, c)
+}
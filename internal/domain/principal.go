@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Principal - аутентифицированный пользователь, от имени которого выполняется запрос
+type Principal struct {
+	UserID  uuid.UUID
+	Roles   []string
+	IsAdmin bool
+}
+
+// CanAccessUser - обычный пользователь видит только свои уведомления, администратор - любые
+func (p *Principal) CanAccessUser(userID uuid.UUID) bool {
+	return p.IsAdmin || p.UserID == userID
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledCancellation - отмена отложенных уведомлений события. Отмена может прийти из другого
+// топика раньше самого события, поэтому она сохраняется и применяется, когда событие будет обработано
+type ScheduledCancellation struct {
+	TargetEventID string    `gorm:"primaryKey"`
+	UserID        uuid.UUID `gorm:"type:uuid;primaryKey"` // uuid.Nil - все получатели события
+	CreatedAt     time.Time
+}
+
+func (*ScheduledCancellation) TableName() string {
+	return "notification_cancellations"
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "errors"
+
+// TransientError - временная ошибка инфраструктуры (таймаут SMTP, недоступность БД).
+// Операцию имеет смысл повторить позже, в отличие от ошибок в самих данных
+type TransientError struct {
+	Err error
+}
+
+func NewTransientError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TransientError{Err: err}
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+func IsTransient(err error) bool {
+	var transient *TransientError
+	return errors.As(err, &transient)
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type ChatMessageNotificationRequest struct {
+	EventID         string
+	UserID          uuid.UUID // получатель сообщения
+	ChatID          string
+	MessageID       string
+	SenderID        uuid.UUID
+	SenderName      string
+	Text            string
+	RecipientEmail  string
+	RecipientOnline bool
+	SendAt          *time.Time // отложенная доставка, nil - сразу
+}
+
+func (r *ChatMessageNotificationRequest) Validate() error {
+	if r.EventID == "" {
+		return domain.ErrMissingEventID
+	}
+	if r.UserID == uuid.Nil {
+		return domain.ErrMissingUserID
+	}
+	if r.ChatID == "" {
+		return domain.ErrMissingChatID
+	}
+	if r.SenderID == uuid.Nil {
+		return domain.ErrMissingSenderID
+	}
+	return nil
+}
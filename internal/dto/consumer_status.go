@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// TopicStatus - состояние консьюмера одного Kafka топика
+type TopicStatus struct {
+	Topic         string     `json:"topic"`
+	GroupID       string     `json:"group_id"`
+	Running       bool       `json:"running"`
+	Processed     uint64     `json:"processed"`
+	Failed        uint64     `json:"failed"`
+	Lag           int64      `json:"lag"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	// UnknownEvents - сколько получено событий каждого незарегистрированного типа
+	UnknownEvents map[string]uint64 `json:"unknown_events,omitempty"`
+}
+
+type ConsumersStatusResponse struct {
+	Status string        `json:"status"`
+	Topics []TopicStatus `json:"topics"`
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type SendEmailNotificationRequest struct {
+	EventID          string
 	UserID           uuid.UUID
 	Email            string
 	DisplayName      string
@@ -27,6 +28,9 @@ func (r *SendEmailNotificationResponse) IsSuccess() bool {
 }
 
 func (r *SendEmailNotificationRequest) Validate() error {
+	if r.EventID == "" {
+		return domain.ErrMissingEventID
+	}
 	if r.UserID == uuid.Nil {
 		return domain.ErrInvalidUUID
 	}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type ListingUpdateNotificationRequest struct {
+	EventID        string
+	UserID         uuid.UUID
+	ListingID      string
+	ListingTitle   string
+	ChangeKind     domain.ListingChangeKind
+	OldPrice       float64
+	NewPrice       float64
+	Currency       string
+	NewStatus      domain.ListingStatus
+	Reason         string
+	RecipientEmail string
+	SendAt         *time.Time // отложенная доставка, nil - сразу
+}
+
+func (r *ListingUpdateNotificationRequest) Validate() error {
+	if r.EventID == "" {
+		return domain.ErrMissingEventID
+	}
+	if r.UserID == uuid.Nil {
+		return domain.ErrMissingUserID
+	}
+	if r.ListingID == "" {
+		return domain.ErrMissingListingID
+	}
+
+	switch r.ChangeKind {
+	case domain.ListingPriceChanged, domain.ListingModerationRejected:
+	case domain.ListingStatusChanged:
+		switch r.NewStatus {
+		case domain.ListingStatusSold, domain.ListingStatusArchived, domain.ListingStatusModerated:
+		default:
+			return domain.ErrUnknownListingStatus
+		}
+	default:
+		return domain.ErrUnknownListingChange
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+const (
+	DefaultNotificationsLimit = 20
+	MaxNotificationsLimit     = 100
+)
+
+type ListNotificationsRequest struct {
+	UserID uuid.UUID
+	Limit  int
+	Offset int
+}
+
+func (r *ListNotificationsRequest) Validate() error {
+	if r.UserID == uuid.Nil {
+		return domain.ErrMissingUserID
+	}
+	return nil
+}
+
+// Normalize подставляет значения пагинации по умолчанию и ограничивает размер страницы
+func (r *ListNotificationsRequest) Normalize() {
+	if r.Limit <= 0 {
+		r.Limit = DefaultNotificationsLimit
+	}
+	if r.Limit > MaxNotificationsLimit {
+		r.Limit = MaxNotificationsLimit
+	}
+	if r.Offset < 0 {
+		r.Offset = 0
+	}
+}
+
+type NotificationResponse struct {
+	ID        uuid.UUID                 `json:"id"`
+	UserID    uuid.UUID                 `json:"user_id"`
+	Type      domain.NotificationType   `json:"type"`
+	Title     string                    `json:"title"`
+	Message   string                    `json:"message"`
+	Metadata  domain.JSONB              `json:"metadata"`
+	Status    domain.NotificationStatus `json:"status"`
+	IsRead    bool                      `json:"is_read"`
+	SendAt    *time.Time                `json:"send_at,omitempty"`
+	SentAt    *time.Time                `json:"sent_at,omitempty"`
+	ReadAt    *time.Time                `json:"read_at,omitempty"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+func NewNotificationResponse(n *domain.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        n.Id,
+		UserID:    n.UserID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Message:   n.Message,
+		Metadata:  n.Metadata,
+		Status:    n.Status,
+		IsRead:    n.ReadAt != nil,
+		SendAt:    n.SendAt,
+		SentAt:    n.SentAt,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}
+}
+
+type NotificationListResponse struct {
+	Items  []NotificationResponse `json:"items"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+func NewNotificationListResponse(notifications []domain.Notification, limit, offset int) NotificationListResponse {
+	items := make([]NotificationResponse, 0, len(notifications))
+	for i := range notifications {
+		items = append(items, NewNotificationResponse(&notifications[i]))
+	}
+
+	return NotificationListResponse{
+		Items:  items,
+		Limit:  limit,
+		Offset: offset,
+	}
+}
+
+type UnreadCountResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Count  int64     `json:"count"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
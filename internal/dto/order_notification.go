@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+// OrderParticipant - покупатель или продавец заказа
+type OrderParticipant struct {
+	UserID uuid.UUID
+	Name   string
+	Email  string
+}
+
+type OrderNotificationRequest struct {
+	EventID      string
+	OrderID      string
+	ListingID    string
+	ListingTitle string
+	Buyer        OrderParticipant
+	Seller       OrderParticipant
+	Amount       float64
+	Currency     string
+	// Status не задается для order.created
+	Status         domain.OrderStatus
+	TrackingNumber string
+	Reason         string
+	SendAt         *time.Time // отложенная доставка, nil - сразу
+}
+
+func (r *OrderNotificationRequest) Validate() error {
+	if r.EventID == "" {
+		return domain.ErrMissingEventID
+	}
+	if r.OrderID == "" {
+		return domain.ErrMissingOrderID
+	}
+	if r.Buyer.UserID == uuid.Nil {
+		return domain.ErrMissingBuyerID
+	}
+	if r.Seller.UserID == uuid.Nil {
+		return domain.ErrMissingSellerID
+	}
+	// оба уведомления адресованы одному пользователю, и второе совпало бы с первым по event_id
+	if r.Buyer.UserID == r.Seller.UserID {
+		return domain.ErrBuyerIsSeller
+	}
+	return nil
+}
+
+func (r *OrderNotificationRequest) ValidateStatusChange() error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	switch r.Status {
+	case domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusDelivered,
+		domain.OrderStatusCancelled, domain.OrderStatusDisputed:
+		return nil
+	default:
+		return domain.ErrUnknownOrderStatus
+	}
+}
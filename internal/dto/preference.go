@@ -0,0 +1,172 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type PreferenceItem struct {
+	Type      domain.NotificationType `json:"type"`
+	Channel   domain.DeliveryChannel  `json:"channel"`
+	Enabled   bool                    `json:"enabled"`
+	Mandatory bool                    `json:"mandatory"`
+}
+
+// QuietHours - окно тихих часов в формате HH:MM по часовому поясу пользователя
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type PreferencesResponse struct {
+	UserID     uuid.UUID         `json:"user_id"`
+	Timezone   string            `json:"timezone"`
+	QuietHours *QuietHours       `json:"quiet_hours,omitempty"`
+	DigestMode domain.DigestMode `json:"digest_mode"`
+	Items      []PreferenceItem  `json:"items"`
+}
+
+// NewPreferencesResponse разворачивает сохраненные настройки в полную матрицу тип x канал,
+// чтобы клиенту не нужно было знать значения по умолчанию
+func NewPreferencesResponse(
+	settings *domain.DeliverySettings,
+	preferences []domain.NotificationPreference,
+) PreferencesResponse {
+	type key struct {
+		notificationType domain.NotificationType
+		channel          domain.DeliveryChannel
+	}
+	saved := make(map[key]bool, len(preferences))
+	for _, preference := range preferences {
+		saved[key{preference.Type, preference.Channel}] = preference.Enabled
+	}
+
+	items := make([]PreferenceItem, 0, len(domain.NotificationTypes)*len(domain.DeliveryChannels))
+	for _, notificationType := range domain.NotificationTypes {
+		for _, channel := range domain.DeliveryChannels {
+			enabled, ok := saved[key{notificationType, channel}]
+			if !ok || notificationType.IsMandatory() {
+				enabled = true
+			}
+
+			items = append(items, PreferenceItem{
+				Type:      notificationType,
+				Channel:   channel,
+				Enabled:   enabled,
+				Mandatory: notificationType.IsMandatory(),
+			})
+		}
+	}
+
+	response := PreferencesResponse{
+		UserID:     settings.UserID,
+		Timezone:   settings.Timezone,
+		DigestMode: settings.DigestMode,
+		Items:      items,
+	}
+	if settings.HasQuietHours() {
+		response.QuietHours = &QuietHours{
+			Start: domain.FormatClock(*settings.QuietHoursStart),
+			End:   domain.FormatClock(*settings.QuietHoursEnd),
+		}
+	}
+
+	return response
+}
+
+type PreferenceUpdate struct {
+	Type    domain.NotificationType `json:"type"`
+	Channel domain.DeliveryChannel  `json:"channel"`
+	Enabled bool                    `json:"enabled"`
+}
+
+// UpdatePreferencesRequest - все поля необязательны, непереданные настройки не меняются.
+// Тихие часы отключаются передачей пустых start и end
+type UpdatePreferencesRequest struct {
+	UserID      uuid.UUID          `json:"-"`
+	Timezone    *string            `json:"timezone,omitempty"`
+	QuietHours  *QuietHours        `json:"quiet_hours,omitempty"`
+	DigestMode  *domain.DigestMode `json:"digest_mode,omitempty"`
+	Preferences []PreferenceUpdate `json:"preferences"`
+}
+
+func (r *UpdatePreferencesRequest) Validate() error {
+	if r.UserID == uuid.Nil {
+		return domain.ErrMissingUserID
+	}
+	if len(r.Preferences) == 0 && !r.ChangesSettings() {
+		return domain.ErrEmptyPreferences
+	}
+
+	if r.Timezone != nil {
+		if _, err := time.LoadLocation(*r.Timezone); err != nil || *r.Timezone == "" || *r.Timezone == "Local" {
+			return domain.ErrInvalidTimezone
+		}
+	}
+	if r.DigestMode != nil && !r.DigestMode.IsValid() {
+		return domain.ErrUnknownDigestMode
+	}
+	if r.QuietHours != nil && (r.QuietHours.Start != "" || r.QuietHours.End != "") {
+		start, err := domain.ParseClock(r.QuietHours.Start)
+		if err != nil {
+			return err
+		}
+		end, err := domain.ParseClock(r.QuietHours.End)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return domain.ErrInvalidQuietHours
+		}
+	}
+
+	// upsert не может изменить одну строку дважды, поэтому повтор пары - ошибка клиента
+	seen := make(map[PreferenceUpdate]bool, len(r.Preferences))
+	for _, preference := range r.Preferences {
+		key := PreferenceUpdate{Type: preference.Type, Channel: preference.Channel}
+		if seen[key] {
+			return domain.ErrDuplicatePreference
+		}
+		seen[key] = true
+
+		if !preference.Type.IsValid() {
+			return domain.ErrUnknownNotificationType
+		}
+		if !preference.Channel.IsValid() {
+			return domain.ErrUnknownDeliveryChannel
+		}
+		if !preference.Enabled && preference.Type.IsMandatory() {
+			return domain.ErrMandatoryNotification
+		}
+	}
+
+	return nil
+}
+
+func (r *UpdatePreferencesRequest) ChangesSettings() bool {
+	return r.Timezone != nil || r.QuietHours != nil || r.DigestMode != nil
+}
+
+// ApplyTo переносит изменения часового пояса, тихих часов и режима сводок на текущие настройки.
+// Вызывается после Validate
+func (r *UpdatePreferencesRequest) ApplyTo(settings *domain.DeliverySettings) {
+	if r.Timezone != nil {
+		settings.Timezone = *r.Timezone
+	}
+	if r.DigestMode != nil {
+		settings.DigestMode = *r.DigestMode
+	}
+
+	if r.QuietHours != nil {
+		if r.QuietHours.Start == "" && r.QuietHours.End == "" {
+			settings.QuietHoursStart, settings.QuietHoursEnd = nil, nil
+			return
+		}
+
+		start, _ := domain.ParseClock(r.QuietHours.Start)
+		end, _ := domain.ParseClock(r.QuietHours.End)
+		settings.QuietHoursStart, settings.QuietHoursEnd = &start, &end
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+func TestUpdatePreferencesRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences []PreferenceUpdate
+		wantErr     error
+	}{
+		{
+			name: "different channels of one type",
+			preferences: []PreferenceUpdate{
+				{Type: domain.TypeNewMessage, Channel: domain.ChannelEmail},
+				{Type: domain.TypeNewMessage, Channel: domain.ChannelPush},
+			},
+		},
+		{
+			name: "same type and channel twice",
+			preferences: []PreferenceUpdate{
+				{Type: domain.TypeNewMessage, Channel: domain.ChannelEmail, Enabled: false},
+				{Type: domain.TypeNewMessage, Channel: domain.ChannelEmail, Enabled: true},
+			},
+			wantErr: domain.ErrDuplicatePreference,
+		},
+		{
+			name:        "unknown type",
+			preferences: []PreferenceUpdate{{Type: "promo", Channel: domain.ChannelEmail}},
+			wantErr:     domain.ErrUnknownNotificationType,
+		},
+		{
+			name:        "unknown channel",
+			preferences: []PreferenceUpdate{{Type: domain.TypeNewMessage, Channel: "sms"}},
+			wantErr:     domain.ErrUnknownDeliveryChannel,
+		},
+		{
+			name:        "mandatory type disabled",
+			preferences: []PreferenceUpdate{{Type: domain.TypeEmailVerification, Channel: domain.ChannelEmail}},
+			wantErr:     domain.ErrMandatoryNotification,
+		},
+		{
+			name:    "nothing to update",
+			wantErr: domain.ErrEmptyPreferences,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := UpdatePreferencesRequest{UserID: uuid.New(), Preferences: tt.preferences}
+
+			if err := req.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+)
+
+type ReviewReceivedNotificationRequest struct {
+	EventID        string
+	UserID         uuid.UUID // продавец, которому оставили отзыв
+	ReviewID       string
+	ListingID      string
+	ListingTitle   string
+	ReviewerID     uuid.UUID
+	ReviewerName   string
+	Rating         int
+	Text           string
+	RecipientEmail string
+	// Metadata - дополнительные поля от сервиса отзывов, сохраняются в уведомлении как есть
+	Metadata map[string]interface{}
+	SendAt   *time.Time // отложенная доставка, nil - сразу
+}
+
+func (r *ReviewReceivedNotificationRequest) Validate() error {
+	if r.EventID == "" {
+		return domain.ErrMissingEventID
+	}
+	if r.UserID == uuid.Nil {
+		return domain.ErrMissingUserID
+	}
+	if r.ReviewID == "" {
+		return domain.ErrMissingReviewID
+	}
+	if r.Rating < MinReviewRating || r.Rating > MaxReviewRating {
+		return domain.ErrInvalidRating
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type CancelScheduledRequest struct {
+	EventID       string
+	TargetEventID string    // событие, из которого были созданы отложенные уведомления
+	UserID        uuid.UUID // uuid.Nil - отменить для всех получателей
+}
+
+func (r *CancelScheduledRequest) Validate() error {
+	if r.EventID == "" {
+		return domain.ErrMissingEventID
+	}
+	if r.TargetEventID == "" {
+		return domain.ErrMissingTargetEventID
+	}
+	return nil
+}
@@ -1,17 +1,46 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/usecase"
 )
 
 type Handler struct {
-	// пока оставил пустым, так как сейчас все необходимое обрабатывает kafka handler
+	inboxUseCase      *usecase.InboxUseCase
+	preferenceUseCase *usecase.PreferenceUseCase
+	subscriber        NotificationSubscriber
+	consumers         ConsumerStatusProvider
+	heartbeatInterval time.Duration
+}
+
+// ConsumerStatusProvider отдает состояние Kafka консьюмеров по топикам
+type ConsumerStatusProvider interface {
+	TopicStatuses() []model.TopicStatus
 }
 
-func NewHandler() *Handler {
-	return &Handler{}
+func NewHandler(
+	inboxUseCase *usecase.InboxUseCase,
+	preferenceUseCase *usecase.PreferenceUseCase,
+	subscriber NotificationSubscriber,
+	consumers ConsumerStatusProvider,
+	heartbeatInterval time.Duration,
+) *Handler {
+	return &Handler{
+		inboxUseCase:      inboxUseCase,
+		preferenceUseCase: preferenceUseCase,
+		subscriber:        subscriber,
+		consumers:         consumers,
+		heartbeatInterval: heartbeatInterval,
+	}
 }
 
 func (h *Handler) HealthCheck(c *gin.Context) {
@@ -20,3 +49,216 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 		"service": "notification-service",
 	})
 }
+
+// ConsumersStatus - состояние консьюмеров по топикам, 503 если какой-то из них остановлен
+func (h *Handler) ConsumersStatus(c *gin.Context) {
+	topics := h.consumers.TopicStatuses()
+
+	status, code := "ok", http.StatusOK
+	for _, topic := range topics {
+		if !topic.Running {
+			status, code = "degraded", http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, model.ConsumersStatusResponse{
+		Status: status,
+		Topics: topics,
+	})
+}
+
+func (h *Handler) ListNotifications(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	req := model.ListNotificationsRequest{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	}
+	req.Normalize()
+
+	notifications, err := h.inboxUseCase.ListNotifications(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewNotificationListResponse(notifications, req.Limit, req.Offset))
+}
+
+func (h *Handler) GetNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, domain.ErrInvalidNotificationID)
+		return
+	}
+
+	notification, err := h.inboxUseCase.GetNotification(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewNotificationResponse(notification))
+}
+
+func (h *Handler) MarkAsRead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, domain.ErrInvalidNotificationID)
+		return
+	}
+
+	notification, err := h.inboxUseCase.MarkAsRead(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewNotificationResponse(notification))
+}
+
+func (h *Handler) CountUnread(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	count, err := h.inboxUseCase.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if userID == uuid.Nil {
+		if principal, ok := domain.PrincipalFromContext(c.Request.Context()); ok {
+			userID = principal.UserID
+		}
+	}
+
+	c.JSON(http.StatusOK, model.UnreadCountResponse{
+		UserID: userID,
+		Count:  count,
+	})
+}
+
+func (h *Handler) GetPreferences(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	preferences, err := h.preferenceUseCase.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences - частичное обновление: настройки, не переданные в запросе, не меняются
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	userID, err := queryUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req model.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, errInvalidRequestBody)
+		return
+	}
+	req.UserID = userID
+
+	preferences, err := h.preferenceUseCase.UpdatePreferences(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// queryUserID - user_id необязателен, по умолчанию берется пользователь из токена
+func queryUserID(c *gin.Context) (uuid.UUID, error) {
+	raw := c.Query("user_id")
+	if raw == "" {
+		return uuid.Nil, nil
+	}
+
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, domain.ErrInvalidUUID
+	}
+
+	return userID, nil
+}
+
+var (
+	errInvalidPagination  = errors.New("limit and offset must be integers")
+	errInvalidRequestBody = errors.New("invalid request body")
+)
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, errInvalidPagination
+	}
+
+	return value, nil
+}
+
+// respondError переводит доменные ошибки в HTTP статусы, детали внутренних ошибок наружу не отдаем
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotificationNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Error: domain.ErrNotificationNotFound.Error()})
+	case errors.Is(err, domain.ErrInvalidNotificationID):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Error: domain.ErrInvalidNotificationID.Error()})
+	case errors.Is(err, domain.ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Error: domain.ErrUnauthenticated.Error()})
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, model.ErrorResponse{Error: domain.ErrForbidden.Error()})
+	case errors.Is(err, domain.ErrInvalidUUID),
+		errors.Is(err, domain.ErrMissingUserID),
+		errors.Is(err, errInvalidPagination),
+		errors.Is(err, errInvalidRequestBody),
+		errors.Is(err, domain.ErrEmptyPreferences),
+		errors.Is(err, domain.ErrUnknownNotificationType),
+		errors.Is(err, domain.ErrUnknownDeliveryChannel),
+		errors.Is(err, domain.ErrMandatoryNotification),
+		errors.Is(err, domain.ErrDuplicatePreference),
+		errors.Is(err, domain.ErrInvalidTimezone),
+		errors.Is(err, domain.ErrInvalidClock),
+		errors.Is(err, domain.ErrInvalidQuietHours),
+		errors.Is(err, domain.ErrUnknownDigestMode):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Error: "internal server error"})
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+type TokenVerifier interface {
+	Verify(token string) (*domain.Principal, error)
+}
+
+// AuthMiddleware проверяет bearer JWT и кладет аутентифицированного пользователя
+// в контекст запроса, откуда его забирают use case'ы
+func AuthMiddleware(verifier TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Error: "missing bearer token"})
+			return
+		}
+
+		principal, err := verifier.Verify(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Error: "invalid token"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), principal))
+
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -2,12 +2,25 @@ package http
 
 import "github.com/gin-gonic/gin"
 
-func SetupRouter() *gin.Engine {
+func SetupRouter(handler *Handler, verifier TokenVerifier) *gin.Engine {
 	router := gin.Default()
 
-	handler := NewHandler()
-
 	router.GET("/health", handler.HealthCheck)
+	router.GET("/health/consumers", handler.ConsumersStatus)
+
+	api := router.Group("/api/v1", AuthMiddleware(verifier))
+	{
+		notifications := api.Group("/notifications")
+		notifications.GET("", handler.ListNotifications)
+		notifications.GET("/unread-count", handler.CountUnread)
+		notifications.GET("/stream", handler.StreamNotifications)
+		notifications.GET("/:id", handler.GetNotification)
+		notifications.POST("/:id/read", handler.MarkAsRead)
+
+		preferences := api.Group("/preferences")
+		preferences.GET("", handler.GetPreferences)
+		preferences.PUT("", handler.UpdatePreferences)
+	}
 
 	return router
 }
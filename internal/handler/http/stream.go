@@ -0,0 +1,160 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+const (
+	sseEventNotification = "notification"
+	// на каждую запись в поток даем отдельный дедлайн, общий WriteTimeout сервера закрыл бы SSE через 10 секунд
+	sseWriteTimeout = 10 * time.Second
+)
+
+type NotificationSubscriber interface {
+	Subscribe(userID uuid.UUID) (<-chan *domain.Notification, func())
+}
+
+// StreamNotifications - SSE поток новых уведомлений пользователя.
+// Поддерживает возобновление по Last-Event-ID (заголовок или query параметр last_event_id)
+func (h *Handler) StreamNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	requested, err := queryUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	userID, err := h.inboxUseCase.ResolveStreamUser(ctx, requested)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	lastEventID, err := lastEventID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// подписываемся до выборки пропущенных, чтобы не потерять уведомления, созданные между ними
+	updates, unsubscribe := h.subscriber.Subscribe(userID)
+	defer unsubscribe()
+
+	var missed []domain.Notification
+	if lastEventID != uuid.Nil {
+		missed, err = h.inboxUseCase.ListMissed(ctx, userID, lastEventID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	send := func(write func(w io.Writer) error) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+		if err := write(c.Writer); err != nil {
+			log.Printf("SSE write error for user %s: %v", userID, err)
+			return false
+		}
+		if err := rc.Flush(); err != nil {
+			log.Printf("SSE flush error for user %s: %v", userID, err)
+			return false
+		}
+		return true
+	}
+
+	if !send(func(w io.Writer) error { return writeSSERetry(w, h.heartbeatInterval) }) {
+		return
+	}
+
+	// одно и то же уведомление может прийти и в выборке пропущенных, и из live канала,
+	// повторно отдаем его только если оно изменилось (например, прочитано на другом устройстве)
+	delivered := make(map[uuid.UUID]time.Time, len(missed))
+	for i := range missed {
+		delivered[missed[i].Id] = missed[i].UpdatedAt
+		if !send(func(w io.Writer) error { return writeSSENotification(w, &missed[i]) }) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification, ok := <-updates:
+			if !ok {
+				return
+			}
+			if updatedAt, seen := delivered[notification.Id]; seen && !notification.UpdatedAt.After(updatedAt) {
+				continue
+			}
+			delivered[notification.Id] = notification.UpdatedAt
+			if !send(func(w io.Writer) error { return writeSSENotification(w, notification) }) {
+				return
+			}
+		case <-heartbeat.C:
+			if !send(writeSSEHeartbeat) {
+				return
+			}
+		}
+	}
+}
+
+func lastEventID(c *gin.Context) (uuid.UUID, error) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, domain.ErrInvalidNotificationID
+	}
+
+	return id, nil
+}
+
+func writeSSENotification(w io.Writer, notification *domain.Notification) error {
+	data, err := json.Marshal(model.NewNotificationResponse(notification))
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", notification.Id, sseEventNotification, data)
+	return err
+}
+
+func writeSSEHeartbeat(w io.Writer) error {
+	_, err := fmt.Fprintf(w, ": heartbeat %d\n\n", time.Now().Unix())
+	return err
+}
+
+// writeSSERetry подсказывает браузеру интервал переподключения
+func writeSSERetry(w io.Writer, interval time.Duration) error {
+	_, err := fmt.Fprintf(w, "retry: %d\n\n", interval.Milliseconds())
+	return err
+}
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS notify_notifications_change ON notifications;
+DROP FUNCTION IF EXISTS notify_notification_change();
@@ -0,0 +1,19 @@
+CREATE OR REPLACE FUNCTION notify_notification_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify(
+        'notification_changes',
+        json_build_object(
+            'id', NEW.id,
+            'user_id', NEW.user_id,
+            'operation', TG_OP
+        )::text
+    );
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER notify_notifications_change
+    AFTER INSERT OR UPDATE ON notifications
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_notification_change();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_event_id_user_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_id;
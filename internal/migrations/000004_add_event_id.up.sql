@@ -0,0 +1,9 @@
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS event_id VARCHAR(255);
+
+-- одно событие может адресоваться нескольким пользователям (покупатель и продавец),
+-- поэтому уникальность по паре event_id + user_id
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_event_id_user_id
+    ON notifications(event_id, user_id)
+    WHERE event_id IS NOT NULL;
+
+COMMENT ON COLUMN notifications.event_id IS 'ID Kafka события, из которого создано уведомление (для идемпотентной обработки)';
@@ -0,0 +1,7 @@
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_status;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempt_count,
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS sent_at;
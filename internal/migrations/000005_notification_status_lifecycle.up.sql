@@ -0,0 +1,13 @@
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS sent_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS attempt_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE notifications
+    ADD CONSTRAINT chk_notifications_status
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'retrying', 'cancelled'));
+
+COMMENT ON COLUMN notifications.status IS 'Статус уведомления (pending, sending, sent, failed, retrying, cancelled)';
+COMMENT ON COLUMN notifications.attempt_count IS 'Количество попыток доставки';
+COMMENT ON COLUMN notifications.last_error IS 'Ошибка последней неудачной попытки доставки';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notifications_sending_locked_until;
+DROP INDEX IF EXISTS idx_notifications_next_attempt_at;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS next_attempt_at;
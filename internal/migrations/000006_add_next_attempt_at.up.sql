@@ -0,0 +1,14 @@
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_next_attempt_at
+    ON notifications(next_attempt_at)
+    WHERE status IN ('failed', 'retrying') AND next_attempt_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_sending_locked_until
+    ON notifications(locked_until)
+    WHERE status = 'sending' AND locked_until IS NOT NULL;
+
+COMMENT ON COLUMN notifications.next_attempt_at IS 'Время следующей попытки доставки для retry воркера';
+COMMENT ON COLUMN notifications.locked_until IS 'Аренда попытки retry воркера, после истечения зависшее в sending уведомление забирается снова';
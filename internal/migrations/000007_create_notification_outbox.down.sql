@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notification_outbox_available_at;
+DROP INDEX IF EXISTS idx_notification_outbox_notification_channel;
+DROP TABLE IF EXISTS notification_outbox;
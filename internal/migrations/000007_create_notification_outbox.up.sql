@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    available_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_outbox_notification_channel
+    ON notification_outbox(notification_id, channel);
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_available_at
+    ON notification_outbox(available_at)
+    WHERE processed_at IS NULL;
+
+COMMENT ON TABLE notification_outbox IS 'Transactional outbox: задачи доставки уведомлений по каналам, пишутся в одной транзакции с уведомлением';
+COMMENT ON COLUMN notification_outbox.channel IS 'Канал доставки (email, push)';
+COMMENT ON COLUMN notification_outbox.locked_until IS 'Аренда записи диспетчером, после истечения запись может забрать другая реплика';
@@ -0,0 +1,7 @@
+DELETE FROM notification_outbox WHERE outcome <> '';
+
+DROP INDEX IF EXISTS idx_notification_outbox_notification_channel;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_outbox_notification_channel
+    ON notification_outbox(notification_id, channel);
+
+ALTER TABLE notification_outbox DROP COLUMN IF EXISTS outcome;
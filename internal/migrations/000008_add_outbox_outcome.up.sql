@@ -0,0 +1,10 @@
+ALTER TABLE notification_outbox ADD COLUMN IF NOT EXISTS outcome VARCHAR(50) NOT NULL DEFAULT '';
+
+-- итогов доставки у одного уведомления может быть несколько (повторная отправка после failed),
+-- уникальность сохраняется только для задач доставки по каналам
+DROP INDEX IF EXISTS idx_notification_outbox_notification_channel;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_outbox_notification_channel
+    ON notification_outbox(notification_id, channel)
+    WHERE outcome = '';
+
+COMMENT ON COLUMN notification_outbox.outcome IS 'Итог доставки для публикации в Kafka (notification.sent, notification.failed, notification.read), пусто у задач доставки по каналам';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, type, channel)
+);
+
+COMMENT ON TABLE notification_preferences IS 'Настройки доставки уведомлений пользователя по типам и каналам, отсутствие записи - канал включен';
+COMMENT ON COLUMN notification_preferences.channel IS 'Канал доставки (email, push)';
@@ -0,0 +1,8 @@
+UPDATE notifications SET status = 'sent' WHERE status = 'skipped';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_status;
+ALTER TABLE notifications
+    ADD CONSTRAINT chk_notifications_status
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'retrying', 'cancelled'));
+
+COMMENT ON COLUMN notifications.status IS 'Статус уведомления (pending, sending, sent, failed, retrying, cancelled)';
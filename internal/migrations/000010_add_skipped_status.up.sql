@@ -0,0 +1,6 @@
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_status;
+ALTER TABLE notifications
+    ADD CONSTRAINT chk_notifications_status
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'retrying', 'cancelled', 'skipped'));
+
+COMMENT ON COLUMN notifications.status IS 'Статус уведомления (pending, sending, sent, failed, retrying, cancelled, skipped)';
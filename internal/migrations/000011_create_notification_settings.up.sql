@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id UUID PRIMARY KEY,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    quiet_hours_start SMALLINT CHECK (quiet_hours_start BETWEEN 0 AND 1439),
+    quiet_hours_end SMALLINT CHECK (quiet_hours_end BETWEEN 0 AND 1439),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE notification_settings IS 'Часовой пояс и тихие часы пользователя, отсутствие записи - UTC без тихих часов';
+COMMENT ON COLUMN notification_settings.timezone IS 'Часовой пояс IANA (Europe/Moscow)';
+COMMENT ON COLUMN notification_settings.quiet_hours_start IS 'Начало тихих часов в минутах от начала суток по часовому поясу пользователя';
+COMMENT ON COLUMN notification_settings.quiet_hours_end IS 'Конец тихих часов, может быть меньше начала, если окно переходит через полночь';
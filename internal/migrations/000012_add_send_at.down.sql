@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_notifications_scheduled_send_at;
+
+UPDATE notifications SET status = 'cancelled' WHERE status = 'scheduled';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_status;
+ALTER TABLE notifications
+    ADD CONSTRAINT chk_notifications_status
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'retrying', 'cancelled', 'skipped'));
+
+COMMENT ON COLUMN notifications.status IS 'Статус уведомления (pending, sending, sent, failed, retrying, cancelled, skipped)';
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS send_at;
@@ -0,0 +1,13 @@
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS send_at TIMESTAMP;
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_status;
+ALTER TABLE notifications
+    ADD CONSTRAINT chk_notifications_status
+    CHECK (status IN ('scheduled', 'pending', 'sending', 'sent', 'failed', 'retrying', 'cancelled', 'skipped'));
+
+CREATE INDEX IF NOT EXISTS idx_notifications_scheduled_send_at
+    ON notifications(send_at)
+    WHERE status = 'scheduled';
+
+COMMENT ON COLUMN notifications.status IS 'Статус уведомления (scheduled, pending, sending, sent, failed, retrying, cancelled, skipped)';
+COMMENT ON COLUMN notifications.send_at IS 'Время отложенной доставки, до него уведомление в статусе scheduled';
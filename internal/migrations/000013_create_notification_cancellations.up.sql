@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS notification_cancellations (
+    target_event_id VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (target_event_id, user_id)
+);
+
+COMMENT ON TABLE notification_cancellations IS 'Отмены отложенных уведомлений, в том числе пришедшие раньше исходного события';
+COMMENT ON COLUMN notification_cancellations.user_id IS 'Получатель, нулевой UUID - все получатели события';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_notifications_digest_at;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS digested_at,
+    DROP COLUMN IF EXISTS digest_at;
+
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS digest_mode;
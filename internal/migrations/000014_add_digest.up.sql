@@ -0,0 +1,15 @@
+ALTER TABLE notification_settings
+    ADD COLUMN IF NOT EXISTS digest_mode VARCHAR(20) NOT NULL DEFAULT 'instant'
+    CHECK (digest_mode IN ('instant', 'hourly', 'daily'));
+
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS digest_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS digested_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_digest_at
+    ON notifications(digest_at)
+    WHERE digest_at IS NOT NULL AND digested_at IS NULL;
+
+COMMENT ON COLUMN notification_settings.digest_mode IS 'Режим писем о частых уведомлениях (instant, hourly, daily)';
+COMMENT ON COLUMN notifications.digest_at IS 'Время сводки, в которую попадет уведомление вместо отдельного письма';
+COMMENT ON COLUMN notifications.digested_at IS 'Когда отправлена сводка с уведомлением';
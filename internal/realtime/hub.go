@@ -0,0 +1,86 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+const subscriberBufferSize = 16
+
+// Hub раздает новые уведомления активным подключениям пользователей внутри процесса.
+// Медленный подписчик не блокирует остальных: если его буфер заполнен, событие пропускается,
+// а клиент догоняет пропущенное через Last-Event-ID при переподключении
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch chan *domain.Notification
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[uuid.UUID]map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe возвращает канал уведомлений пользователя и функцию отписки,
+// которую обязательно нужно вызвать при закрытии соединения
+func (h *Hub) Subscribe(userID uuid.UUID) (<-chan *domain.Notification, func()) {
+	sub := &subscriber{
+		ch: make(chan *domain.Notification, subscriberBufferSize),
+	}
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[userID], sub)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			h.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+
+	return sub.ch, unsubscribe
+}
+
+func (h *Hub) Publish(_ context.Context, notification *domain.Notification) {
+	if notification == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers[notification.UserID] {
+		select {
+		case sub.ch <- notification:
+		default:
+			log.Printf("Realtime subscriber buffer is full, dropping notification %s for user %s",
+				notification.Id, notification.UserID)
+		}
+	}
+}
+
+func (h *Hub) HasSubscribers(userID uuid.UUID) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subscribers[userID]) > 0
+}
@@ -0,0 +1,48 @@
+package realtime
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type NotificationLoader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error)
+}
+
+// Relay получает ID измененных уведомлений (от любой реплики через LISTEN/NOTIFY)
+// и раздает актуальную версию уведомления локальным подписчикам Hub
+type Relay struct {
+	loader NotificationLoader
+	hub    *Hub
+}
+
+func NewRelay(loader NotificationLoader, hub *Hub) *Relay {
+	return &Relay{
+		loader: loader,
+		hub:    hub,
+	}
+}
+
+func (r *Relay) Relay(ctx context.Context, id, userID uuid.UUID) {
+	// изменения приходят на все реплики, в базу идем только если пользователь подключен к этой
+	if !r.hub.HasSubscribers(userID) {
+		return
+	}
+
+	notification, err := r.loader.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("Failed to load notification %s for realtime delivery: %v", id, err)
+		return
+	}
+
+	// отложенное уведомление появится у пользователя, когда планировщик его выпустит
+	if !notification.IsVisible() {
+		return
+	}
+
+	r.hub.Publish(ctx, notification)
+}
@@ -8,15 +8,19 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
 )
 
 var (
-	ErrNotificationNotFound  = errors.New("notification not found")
-	ErrInvalidNotificationID = errors.New("invalid notification ID")
+	ErrNotificationNotFound  = domain.ErrNotificationNotFound
+	ErrInvalidNotificationID = domain.ErrInvalidNotificationID
 )
 
+// visibleInInbox - условие domain.Notification.IsVisible для запросов входящих
+const visibleInInbox = "status <> 'scheduled' AND (status <> 'cancelled' OR send_at IS NULL)"
+
 type NotificationRepository struct {
 	db *gorm.DB
 }
@@ -34,12 +38,86 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 
 	result := r.db.WithContext(ctx).Create(notification)
 	if result.Error != nil {
-		return fmt.Errorf("failed to create notification: %w", result.Error)
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return domain.ErrDuplicateEvent
+		}
+		return fmt.Errorf("failed to create notification: %w", transient(result.Error))
 	}
 
 	return nil
 }
 
+// CreateWithOutbox сохраняет уведомления вместе с задачами доставки в outbox одной транзакцией
+func (r *NotificationRepository) CreateWithOutbox(ctx context.Context, deliveries ...domain.PendingDelivery) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, delivery := range deliveries {
+			if delivery.Notification == nil {
+				return errors.New("notification cannot be nil")
+			}
+
+			cancelled, err := isCancelledInAdvance(tx, delivery.Notification)
+			if err != nil {
+				return err
+			}
+			if cancelled {
+				if err := delivery.Notification.Cancel(); err != nil {
+					return err
+				}
+				delivery.Channels = nil
+			}
+
+			if err := tx.Create(delivery.Notification).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return domain.ErrDuplicateEvent
+				}
+				return fmt.Errorf("failed to create notification: %w", transient(err))
+			}
+
+			if err := createInAppOutcome(tx, delivery.Notification); err != nil {
+				return err
+			}
+
+			availableAt := delivery.Notification.CreatedAt
+			if delivery.DeliverAfter.After(availableAt) {
+				availableAt = delivery.DeliverAfter
+			}
+
+			for _, channel := range delivery.Channels {
+				message := &domain.OutboxMessage{
+					NotificationID: delivery.Notification.Id,
+					Channel:        channel,
+					AvailableAt:    availableAt,
+				}
+				if err := tx.Create(message).Error; err != nil {
+					return fmt.Errorf("failed to create outbox message: %w", transient(err))
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *NotificationRepository) ExistsByEventID(ctx context.Context, eventID string) (bool, error) {
+	if eventID == "" {
+		return false, domain.ErrMissingEventID
+	}
+
+	var count int64
+	result := r.db.WithContext(ctx).
+		Model(&domain.Notification{}).
+		Where("event_id = ?", eventID).
+		Limit(1).
+		Count(&count)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to check event: %w", transient(result.Error))
+	}
+
+	return count > 0, nil
+}
+
+// GetByID возвращает уведомление в любом статусе, для воркеров доставки
 func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
 	if id == uuid.Nil {
 		return nil, ErrInvalidNotificationID
@@ -52,7 +130,53 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrNotificationNotFound
 		}
-		return nil, fmt.Errorf("failed to get notification: %w", result.Error)
+		return nil, fmt.Errorf("failed to get notification: %w", transient(result.Error))
+	}
+
+	return &notification, nil
+}
+
+// GetVisibleByID возвращает уведомление, только если оно уже видно во входящих
+func (r *NotificationRepository) GetVisibleByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	if id == uuid.Nil {
+		return nil, ErrInvalidNotificationID
+	}
+
+	var notification domain.Notification
+	result := r.db.WithContext(ctx).
+		Where(visibleInInbox).
+		First(&notification, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", transient(result.Error))
+	}
+
+	return &notification, nil
+}
+
+// GetByIDForUser возвращает уведомление только если оно принадлежит пользователю и видно во входящих,
+// чужое или еще не выпущенное уведомление неотличимо от несуществующего
+func (r *NotificationRepository) GetByIDForUser(ctx context.Context, id, userID uuid.UUID) (*domain.Notification, error) {
+	if id == uuid.Nil {
+		return nil, ErrInvalidNotificationID
+	}
+	if userID == uuid.Nil {
+		return nil, domain.ErrMissingUserID
+	}
+
+	var notification domain.Notification
+	result := r.db.WithContext(ctx).
+		Where(visibleInInbox).
+		First(&notification, "id = ? AND user_id = ?", id, userID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", transient(result.Error))
 	}
 
 	return &notification, nil
@@ -64,25 +188,78 @@ func (r *NotificationRepository) GetByUserID(
 	limit, offset int,
 ) ([]domain.Notification, error) {
 	if userID == uuid.Nil {
-		return nil, errors.New("user ID cannot be empty")
+		return nil, domain.ErrMissingUserID
 	}
 
 	var notifications []domain.Notification
 	result := r.db.WithContext(ctx).
 		Where("user_id = ?", userID).
+		Where(visibleInInbox).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&notifications)
 
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to get notifications: %w", result.Error)
+		return nil, fmt.Errorf("failed to get notifications: %w", transient(result.Error))
+	}
+
+	return notifications, nil
+}
+
+// GetByUserIDAfter возвращает уведомления пользователя, созданные после указанного,
+// в хронологическом порядке. Сравнение по (created_at, id) не теряет записи с одинаковым временем
+func (r *NotificationRepository) GetByUserIDAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	createdAt time.Time,
+	id uuid.UUID,
+	limit int,
+) ([]domain.Notification, error) {
+	if userID == uuid.Nil {
+		return nil, domain.ErrMissingUserID
+	}
+
+	var notifications []domain.Notification
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND (created_at, id) > (?, ?)", userID, createdAt, id).
+		Where(visibleInInbox).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&notifications)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", transient(result.Error))
 	}
 
 	return notifications, nil
 }
 
+// Update сохраняет состояние доставки уведомления (lifecycleColumns). Остальные поля, например read_at,
+// не перезаписываются, поэтому отметка о прочтении не теряется при гонке с воркером доставки.
+// Смена статуса проверяется по текущему статусу в базе под блокировкой строки,
+// так недопустимый переход не пройдет даже при гонке нескольких воркеров
 func (r *NotificationRepository) Update(ctx context.Context, notification *domain.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateNotification(tx, notification)
+	})
+}
+
+// UpdateWithOutcome сохраняет уведомление и задачу публикации итога доставки одной транзакцией
+func (r *NotificationRepository) UpdateWithOutcome(
+	ctx context.Context,
+	notification *domain.Notification,
+	outcome *domain.OutboxMessage,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateNotification(tx, notification); err != nil {
+			return err
+		}
+		return createOutcome(tx, outcome)
+	})
+}
+
+func updateNotification(tx *gorm.DB, notification *domain.Notification) error {
 	if notification == nil {
 		return errors.New("notification cannot be nil")
 	}
@@ -91,9 +268,26 @@ func (r *NotificationRepository) Update(ctx context.Context, notification *domai
 		return ErrInvalidNotificationID
 	}
 
-	result := r.db.WithContext(ctx).Save(notification)
+	var current domain.Notification
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		First(&current, "id = ?", notification.Id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationNotFound
+		}
+		return fmt.Errorf("failed to lock notification: %w", transient(err))
+	}
+
+	if current.Status != notification.Status && !domain.CanTransition(current.Status, notification.Status) {
+		return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidStatusTransition, current.Status, notification.Status)
+	}
+
+	result := tx.Model(notification).
+		Select(lifecycleColumns).
+		Updates(notification)
 	if result.Error != nil {
-		return fmt.Errorf("failed to update notification: %w", result.Error)
+		return fmt.Errorf("failed to update notification: %w", transient(result.Error))
 	}
 
 	if result.RowsAffected == 0 {
@@ -103,41 +297,392 @@ func (r *NotificationRepository) Update(ctx context.Context, notification *domai
 	return nil
 }
 
+// lifecycleColumns - поля, которые меняются по ходу доставки уведомления
+var lifecycleColumns = []string{
+	"status",
+	"attempt_count",
+	"last_error",
+	"next_attempt_at",
+	"locked_until",
+	"sent_at",
+	"failed_at",
+	"digested_at",
+	"updated_at",
+}
+
+func createOutcome(tx *gorm.DB, outcome *domain.OutboxMessage) error {
+	if err := tx.Create(outcome).Error; err != nil {
+		return fmt.Errorf("failed to create %s outbox message: %w", outcome.Outcome, transient(err))
+	}
+	return nil
+}
+
+// createInAppOutcome ставит публикацию notification.sent для уведомления без внешних каналов:
+// оно доставлено, как только попало во входящие, и другого итога у него не будет
+func createInAppOutcome(tx *gorm.DB, notification *domain.Notification) error {
+	if notification.Status != domain.StatusSent || notification.SentAt == nil {
+		return nil
+	}
+	return createOutcome(tx, domain.NewOutcomeMessage(
+		domain.OutcomeSent, notification.Id, domain.ChannelInApp, *notification.SentAt))
+}
+
+// ClaimDueForRetry забирает уведомления, у которых подошло время повторной доставки, и сразу
+// переводит их в sending с арендой до now+lease. Уведомление, застрявшее в sending после истечения
+// аренды (воркер упал посреди отправки), считается прерванной попыткой и забирается снова.
+// SKIP LOCKED позволяет нескольким репликам разбирать очередь параллельно
+func (r *NotificationRepository) ClaimDueForRetry(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// письма уведомлений из сводок повторяет DigestWorker
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("digest_at IS NULL").
+			Where("(status IN ? AND next_attempt_at <= ?) OR (status = ? AND locked_until <= ?)",
+				[]domain.NotificationStatus{domain.StatusFailed, domain.StatusRetrying}, now,
+				domain.StatusSending, now).
+			Order("COALESCE(locked_until, next_attempt_at) ASC").
+			Limit(limit).
+			Find(&notifications).Error
+		if err != nil {
+			return fmt.Errorf("failed to select notifications for retry: %w", transient(err))
+		}
+
+		return claimAttempts(tx, notifications, now, now.Add(lease))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// claimAttempts переводит забранные уведомления в sending с арендой до lockedUntil.
+// Уведомление, застрявшее в sending, сначала отмечается прерванной попыткой
+func claimAttempts(tx *gorm.DB, notifications []domain.Notification, now, lockedUntil time.Time) error {
+	for i := range notifications {
+		if notifications[i].Status == domain.StatusSending {
+			cause := domain.NewTransientError(domain.ErrDeliveryInterrupted)
+			if err := notifications[i].MarkRetrying(cause, now); err != nil {
+				return err
+			}
+		}
+		if err := notifications[i].MarkSending(); err != nil {
+			return err
+		}
+		notifications[i].LockedUntil = &lockedUntil
+
+		err := tx.Model(&notifications[i]).
+			Select("status", "attempt_count", "next_attempt_at", "last_error", "locked_until").
+			Updates(&notifications[i]).Error
+		if err != nil {
+			return fmt.Errorf("failed to claim notification %s: %w", notifications[i].Id, transient(err))
+		}
+	}
+	return nil
+}
+
+// ReleaseDueScheduled выпускает отложенные уведомления, у которых наступил send_at.
+// Уведомление с незавершенными задачами outbox или ждущее сводку уходит в pending и дальше
+// доставляется диспетчером или DigestWorker, без них - сразу sent. SKIP LOCKED позволяет планировщикам нескольких реплик не пересекаться
+func (r *NotificationRepository) ReleaseDueScheduled(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND send_at <= ?", domain.StatusScheduled, now).
+			Order("send_at ASC").
+			Limit(limit).
+			Find(&notifications).Error
+		if err != nil {
+			return fmt.Errorf("failed to select scheduled notifications: %w", transient(err))
+		}
+
+		for i := range notifications {
+			var pending int64
+			err := tx.Model(&domain.OutboxMessage{}).
+				Where("notification_id = ? AND outcome = '' AND processed_at IS NULL", notifications[i].Id).
+				Count(&pending).Error
+			if err != nil {
+				return fmt.Errorf("failed to check outbox for notification %s: %w", notifications[i].Id, transient(err))
+			}
+
+			// письмо об уведомлении из сводки отправит DigestWorker, до этого оно остается в pending
+			hasChannels := pending > 0 || notifications[i].DigestAt != nil
+			if err := notifications[i].Release(now, hasChannels); err != nil {
+				return err
+			}
+
+			err = tx.Model(&notifications[i]).
+				Select("status", "sent_at").
+				Updates(&notifications[i]).Error
+			if err != nil {
+				return fmt.Errorf("failed to release notification %s: %w", notifications[i].Id, transient(err))
+			}
+
+			if err := createInAppOutcome(tx, &notifications[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// CancelScheduledByEventID отменяет еще не выпущенные уведомления, созданные из события eventID.
+// userID == uuid.Nil - отменяются уведомления всех получателей события. Отмена сохраняется,
+// даже если отменять пока нечего: событие могло еще не дойти, CreateWithOutbox применит ее позже
+func (r *NotificationRepository) CancelScheduledByEventID(
+	ctx context.Context,
+	eventID string,
+	userID uuid.UUID,
+) (int64, error) {
+	if eventID == "" {
+		return 0, domain.ErrMissingEventID
+	}
+
+	var cancelled int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockScheduledEvent(tx, eventID); err != nil {
+			return err
+		}
+
+		cancellation := &domain.ScheduledCancellation{TargetEventID: eventID, UserID: userID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(cancellation).Error; err != nil {
+			return fmt.Errorf("failed to save cancellation: %w", transient(err))
+		}
+
+		var notifications []domain.Notification
+		query := tx.Where("event_id = ? AND status = ?", eventID, domain.StatusScheduled)
+		if userID != uuid.Nil {
+			query = query.Where("user_id = ?", userID)
+		}
+		if err := query.Find(&notifications).Error; err != nil {
+			return fmt.Errorf("failed to select scheduled notifications: %w", transient(err))
+		}
+
+		// задачи outbox отмененных уведомлений диспетчер закроет сам, увидев статус cancelled
+		for i := range notifications {
+			if err := notifications[i].Cancel(); err != nil {
+				return err
+			}
+			if err := updateNotification(tx, &notifications[i]); err != nil {
+				return err
+			}
+		}
+
+		cancelled = int64(len(notifications))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return cancelled, nil
+}
+
+// первые ключи advisory lock, второй ключ - хэш идентификатора
+const (
+	scheduledEventLock = 24 // создание и отмена отложенных уведомлений события
+	digestClaimLock    = 25 // сбор сводки пользователя
+)
+
+// lockScheduledEvent упорядочивает создание и отмену отложенных уведомлений одного события,
+// иначе параллельные транзакции не увидят друг друга и отмена потеряется
+func lockScheduledEvent(tx *gorm.DB, eventID string) error {
+	err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", scheduledEventLock, eventID).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock event %s: %w", eventID, transient(err))
+	}
+	return nil
+}
+
+// isCancelledInAdvance - отмена отложенного уведомления пришла раньше самого события
+func isCancelledInAdvance(tx *gorm.DB, notification *domain.Notification) (bool, error) {
+	if notification.Status != domain.StatusScheduled || notification.EventID == nil {
+		return false, nil
+	}
+
+	if err := lockScheduledEvent(tx, *notification.EventID); err != nil {
+		return false, err
+	}
+
+	var count int64
+	err := tx.Model(&domain.ScheduledCancellation{}).
+		Where("target_event_id = ? AND user_id IN ?", *notification.EventID, []uuid.UUID{uuid.Nil, notification.UserID}).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check cancellations: %w", transient(err))
+	}
+
+	return count > 0, nil
+}
+
+// ClaimDueDigests забирает уведомления для сводок не более чем limit пользователей: у которых наступило
+// время сводки, подошел повтор неудавшейся сводки или истекла аренда прерванной отправки.
+// Сводка пользователя забирается целиком под advisory lock по user_id, поэтому две реплики не
+// разделят уведомления одного пользователя на две сводки. Забранные уведомления переводятся
+// в sending с арендой до now+lease
+func (r *NotificationRepository) ClaimDueDigests(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+
+	due := []interface{}{
+		domain.StatusPending, now,
+		[]domain.NotificationStatus{domain.StatusFailed, domain.StatusRetrying}, now,
+		domain.StatusSending, now,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var userIDs []uuid.UUID
+		err := tx.Model(&domain.Notification{}).
+			Distinct("user_id").
+			Where(digestPending).
+			Where(digestDue, due...).
+			Limit(limit).
+			Pluck("user_id", &userIDs).Error
+		if err != nil {
+			return fmt.Errorf("failed to select digest recipients: %w", transient(err))
+		}
+
+		for _, userID := range userIDs {
+			var locked bool
+			err := tx.Raw("SELECT pg_try_advisory_xact_lock(?, hashtext(?))", digestClaimLock, userID.String()).
+				Scan(&locked).Error
+			if err != nil {
+				return fmt.Errorf("failed to lock digest of user %s: %w", userID, transient(err))
+			}
+			if !locked {
+				// сводку этого пользователя сейчас забирает другая реплика
+				continue
+			}
+
+			// условие повторяется под блокировкой: после выбора получателей сводку могла забрать другая реплика
+			var claimed []domain.Notification
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("user_id = ?", userID).
+				Where(digestPending).
+				Where(digestDue, due...).
+				Find(&claimed).Error
+			if err != nil {
+				return fmt.Errorf("failed to select digest notifications: %w", transient(err))
+			}
+			notifications = append(notifications, claimed...)
+		}
+
+		return claimAttempts(tx, notifications, now, now.Add(lease))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+const (
+	digestPending = "digest_at IS NOT NULL AND digested_at IS NULL"
+	digestDue     = "((status = ? AND digest_at <= ?) OR (status IN ? AND next_attempt_at <= ?) OR (status = ? AND locked_until <= ?))"
+)
+
+// UpdateDigest сохраняет итог сводки по всем ее уведомлениям вместе с задачами публикации итогов
+func (r *NotificationRepository) UpdateDigest(
+	ctx context.Context,
+	notifications []domain.Notification,
+	outcomes []*domain.OutboxMessage,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range notifications {
+			if err := updateNotification(tx, &notifications[i]); err != nil {
+				return err
+			}
+		}
+		for _, outcome := range outcomes {
+			if err := createOutcome(tx, outcome); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *NotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 	if id == uuid.Nil {
 		return ErrInvalidNotificationID
 	}
 
-	now := time.Now()
-	result := r.db.WithContext(ctx).
-		Model(&domain.Notification{}).
-		Where("id = ? AND read_at IS NULL", id).
-		Update("read_at", now)
+	return r.markAsRead(ctx, id, uuid.Nil)
+}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to mark notification as read: %w", result.Error)
+func (r *NotificationRepository) MarkAsReadForUser(ctx context.Context, id, userID uuid.UUID) error {
+	if id == uuid.Nil {
+		return ErrInvalidNotificationID
 	}
-
-	if result.RowsAffected == 0 {
-		return ErrNotificationNotFound
+	if userID == uuid.Nil {
+		return domain.ErrMissingUserID
 	}
 
-	return nil
+	return r.markAsRead(ctx, id, userID)
+}
+
+// markAsRead отмечает прочтение видимого во входящих уведомления и в той же транзакции ставит
+// в outbox публикацию notification.read. userID == uuid.Nil - без проверки владельца
+func (r *NotificationRepository) markAsRead(ctx context.Context, id, userID uuid.UUID) error {
+	now := time.Now().UTC()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.Notification{}).
+			Where("id = ? AND read_at IS NULL", id).
+			Where(visibleInInbox)
+		if userID != uuid.Nil {
+			query = query.Where("user_id = ?", userID)
+		}
+
+		result := query.Update("read_at", now)
+
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark notification as read: %w", transient(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrNotificationNotFound
+		}
+
+		return createOutcome(tx, domain.NewOutcomeMessage(domain.OutcomeRead, id, "", now))
+	})
 }
 
 func (r *NotificationRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	if userID == uuid.Nil {
-		return 0, errors.New("user ID cannot be empty")
+		return 0, domain.ErrMissingUserID
 	}
 
 	var count int64
 	result := r.db.WithContext(ctx).
 		Model(&domain.Notification{}).
 		Where("user_id = ? AND read_at IS NULL", userID).
+		Where(visibleInInbox).
 		Count(&count)
 
 	if result.Error != nil {
-		return 0, fmt.Errorf("failed to count unread notifications: %w", result.Error)
+		return 0, fmt.Errorf("failed to count unread notifications: %w", transient(result.Error))
 	}
 
 	return count, nil
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/db"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+// testDB подключается к тестовому PostgreSQL из TEST_DATABASE_URL и применяет миграции,
+// без базы тесты репозитория пропускаются
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	cfg := &config.DatabaseConfig{URL: url, MigrationsPath: "../../migrations"}
+	if err := db.RunMigrations(cfg); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	conn, err := db.NewPostgresDB(cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return conn
+}
+
+// createDigestNotifications создает count ожидающих сводку уведомлений каждому пользователю
+func createDigestNotifications(t *testing.T, repo *NotificationRepository, digestAt time.Time, count int, users ...uuid.UUID) {
+	t.Helper()
+
+	var ids []uuid.UUID
+	for _, userID := range users {
+		for range count {
+			notification := &domain.Notification{
+				UserID:   userID,
+				Type:     domain.TypeNewMessage,
+				Title:    "Новое сообщение",
+				Message:  "Привет",
+				Metadata: domain.JSONB{"email": "user@avigo.ru"},
+				DigestAt: &digestAt,
+			}
+			if err := repo.CreateWithOutbox(context.Background(), domain.NewPendingDelivery(notification)); err != nil {
+				t.Fatalf("failed to create notification: %v", err)
+			}
+			ids = append(ids, notification.Id)
+		}
+	}
+
+	t.Cleanup(func() {
+		repo.db.Where("notification_id IN ?", ids).Delete(&domain.OutboxMessage{})
+		repo.db.Where("id IN ?", ids).Delete(&domain.Notification{})
+	})
+}
+
+func TestClaimDueDigestsConcurrentClaimsDoNotSplitUser(t *testing.T) {
+	repo := NewNotificationRepository(testDB(t))
+	now := time.Now().UTC()
+
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	const perUser = 5
+	createDigestNotifications(t, repo, now.Add(-time.Minute), perUser, users...)
+
+	const replicas = 4
+	claims := make([][]domain.Notification, replicas)
+	errs := make([]error, replicas)
+
+	var wg sync.WaitGroup
+	for i := range replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claims[i], errs[i] = repo.ClaimDueDigests(context.Background(), now, time.Minute, 100)
+		}()
+	}
+	wg.Wait()
+
+	owner := make(map[uuid.UUID]int)
+	counts := make(map[uuid.UUID]int)
+	for i, claimed := range claims {
+		if errs[i] != nil {
+			t.Fatalf("ClaimDueDigests() error = %v", errs[i])
+		}
+		for _, notification := range claimed {
+			if replica, seen := owner[notification.UserID]; seen && replica != i {
+				t.Errorf("digest of user %s split between replicas %d and %d", notification.UserID, replica, i)
+			}
+			owner[notification.UserID] = i
+			counts[notification.UserID]++
+		}
+	}
+
+	for _, userID := range users {
+		if counts[userID] != perUser {
+			t.Errorf("user %s: claimed %d notifications, want %d", userID, counts[userID], perUser)
+		}
+	}
+}
+
+func TestClaimDueDigestsSkipsUserLockedByAnotherReplica(t *testing.T) {
+	repo := NewNotificationRepository(testDB(t))
+	now := time.Now().UTC()
+
+	locked, free := uuid.New(), uuid.New()
+	createDigestNotifications(t, repo, now.Add(-time.Minute), 2, locked, free)
+
+	// другая реплика держит блокировку сводки пользователя locked до конца своей транзакции
+	other := repo.db.Begin()
+	t.Cleanup(func() { other.Rollback() })
+	if err := other.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", digestClaimLock, locked.String()).Error; err != nil {
+		t.Fatalf("failed to take digest lock: %v", err)
+	}
+
+	claimed, err := repo.ClaimDueDigests(context.Background(), now, time.Minute, 100)
+	if err != nil {
+		t.Fatalf("ClaimDueDigests() error = %v", err)
+	}
+	for _, notification := range claimed {
+		if notification.UserID == locked {
+			t.Fatalf("claimed notification %s of a user locked by another replica", notification.Id)
+		}
+	}
+	if len(claimed) != 2 {
+		t.Errorf("claimed %d notifications, want 2 of the free user", len(claimed))
+	}
+
+	other.Rollback()
+	claimed, err = repo.ClaimDueDigests(context.Background(), now, time.Minute, 100)
+	if err != nil {
+		t.Fatalf("ClaimDueDigests() error = %v", err)
+	}
+	if len(claimed) != 2 || claimed[0].UserID != locked {
+		t.Errorf("after the lock is released claimed %d notifications, want 2 of the locked user", len(claimed))
+	}
+}
+
+func scheduledNotification(eventID string, userID uuid.UUID, sendAt time.Time) domain.PendingDelivery {
+	notification := &domain.Notification{
+		UserID:   userID,
+		EventID:  &eventID,
+		Type:     domain.TypeOrderStatusChange,
+		Title:    "Заказ отправлен",
+		Message:  "Продавец отправил заказ",
+		Metadata: domain.JSONB{"email": "user@avigo.ru"},
+		SendAt:   &sendAt,
+	}
+	return domain.NewPendingDelivery(notification, domain.ChannelEmail)
+}
+
+func cleanupEvent(t *testing.T, repo *NotificationRepository, eventID string) {
+	t.Cleanup(func() {
+		repo.db.Where("notification_id IN (?)",
+			repo.db.Model(&domain.Notification{}).Select("id").Where("event_id = ?", eventID),
+		).Delete(&domain.OutboxMessage{})
+		repo.db.Where("event_id = ?", eventID).Delete(&domain.Notification{})
+		repo.db.Where("target_event_id = ?", eventID).Delete(&domain.ScheduledCancellation{})
+	})
+}
+
+func TestCancelScheduledByEventID(t *testing.T) {
+	repo := NewNotificationRepository(testDB(t))
+	ctx := context.Background()
+	sendAt := time.Now().UTC().Add(time.Hour)
+
+	eventID := "evt-" + uuid.NewString()
+	cleanupEvent(t, repo, eventID)
+
+	delivery := scheduledNotification(eventID, uuid.New(), sendAt)
+	if err := repo.CreateWithOutbox(ctx, delivery); err != nil {
+		t.Fatalf("CreateWithOutbox() error = %v", err)
+	}
+
+	cancelled, err := repo.CancelScheduledByEventID(ctx, eventID, uuid.Nil)
+	if err != nil {
+		t.Fatalf("CancelScheduledByEventID() error = %v", err)
+	}
+	if cancelled != 1 {
+		t.Errorf("cancelled %d notifications, want 1", cancelled)
+	}
+
+	stored, err := repo.GetByID(ctx, delivery.Notification.Id)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.Status != domain.StatusCancelled {
+		t.Errorf("status = %s, want %s", stored.Status, domain.StatusCancelled)
+	}
+}
+
+func TestCancelBeforeEventIsAppliedOnCreate(t *testing.T) {
+	repo := NewNotificationRepository(testDB(t))
+	ctx := context.Background()
+	sendAt := time.Now().UTC().Add(time.Hour)
+
+	eventID := "evt-" + uuid.NewString()
+	cleanupEvent(t, repo, eventID)
+	buyer, seller := uuid.New(), uuid.New()
+
+	// отмена только для покупателя пришла раньше события о заказе
+	cancelled, err := repo.CancelScheduledByEventID(ctx, eventID, buyer)
+	if err != nil {
+		t.Fatalf("CancelScheduledByEventID() error = %v", err)
+	}
+	if cancelled != 0 {
+		t.Errorf("cancelled %d notifications before the event, want 0", cancelled)
+	}
+
+	forBuyer := scheduledNotification(eventID, buyer, sendAt)
+	forSeller := scheduledNotification(eventID, seller, sendAt)
+	if err := repo.CreateWithOutbox(ctx, forBuyer, forSeller); err != nil {
+		t.Fatalf("CreateWithOutbox() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		notification *domain.Notification
+		wantStatus   domain.NotificationStatus
+		wantTasks    int64
+	}{
+		{forBuyer.Notification, domain.StatusCancelled, 0},
+		{forSeller.Notification, domain.StatusScheduled, 1},
+	} {
+		stored, err := repo.GetByID(ctx, tt.notification.Id)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if stored.Status != tt.wantStatus {
+			t.Errorf("user %s: status = %s, want %s", stored.UserID, stored.Status, tt.wantStatus)
+		}
+
+		var tasks int64
+		repo.db.Model(&domain.OutboxMessage{}).Where("notification_id = ?", stored.Id).Count(&tasks)
+		if tasks != tt.wantTasks {
+			t.Errorf("user %s: %d outbox tasks, want %d", stored.UserID, tasks, tt.wantTasks)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// Claim забирает готовые к обработке записи outbox и берет их в аренду до now+lease.
+// SKIP LOCKED не дает двум репликам забрать одну запись, а аренда возвращает запись в очередь,
+// если забравшая ее реплика упала
+func (r *OutboxRepository) Claim(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	result := r.db.WithContext(ctx).Raw(`
+		UPDATE notification_outbox
+		SET locked_until = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE processed_at IS NULL
+				AND available_at <= ?
+				AND (locked_until IS NULL OR locked_until <= ?)
+			ORDER BY available_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, now, limit,
+	).Scan(&messages)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", transient(result.Error))
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"processed_at": time.Now().UTC(),
+			"locked_until": nil,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark outbox message as processed: %w", transient(result.Error))
+	}
+
+	return nil
+}
+
+// Release возвращает запись в очередь, следующая попытка не раньше availableAt
+func (r *OutboxRepository) Release(ctx context.Context, id uuid.UUID, availableAt time.Time, lastError string) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"available_at": availableAt,
+			"locked_until": nil,
+			"last_error":   lastError,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to release outbox message: %w", transient(result.Error))
+	}
+
+	return nil
+}
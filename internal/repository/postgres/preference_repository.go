@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type PreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepository(db *gorm.DB) *PreferenceRepository {
+	return &PreferenceRepository{
+		db: db,
+	}
+}
+
+func (r *PreferenceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.NotificationPreference, error) {
+	if userID == uuid.Nil {
+		return nil, domain.ErrMissingUserID
+	}
+
+	var preferences []domain.NotificationPreference
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("type, channel").
+		Find(&preferences)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", transient(result.Error))
+	}
+
+	return preferences, nil
+}
+
+// DisabledChannels возвращает каналы, в которых пользователь отключил уведомления данного типа
+func (r *PreferenceRepository) DisabledChannels(
+	ctx context.Context,
+	userID uuid.UUID,
+	notificationType domain.NotificationType,
+) ([]domain.DeliveryChannel, error) {
+	if userID == uuid.Nil {
+		return nil, domain.ErrMissingUserID
+	}
+
+	var channels []domain.DeliveryChannel
+	result := r.db.WithContext(ctx).
+		Model(&domain.NotificationPreference{}).
+		Where("user_id = ? AND type = ? AND NOT enabled", userID, notificationType).
+		Pluck("channel", &channels)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get disabled channels: %w", transient(result.Error))
+	}
+
+	return channels, nil
+}
+
+// GetSettings возвращает настройки по умолчанию, если пользователь их не менял
+func (r *PreferenceRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*domain.DeliverySettings, error) {
+	if userID == uuid.Nil {
+		return nil, domain.ErrMissingUserID
+	}
+
+	var settings domain.DeliverySettings
+	result := r.db.WithContext(ctx).Limit(1).Find(&settings, "user_id = ?", userID)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", transient(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return domain.DefaultDeliverySettings(userID), nil
+	}
+
+	return &settings, nil
+}
+
+// Update сохраняет настройки пользователя и настройки по типам одной транзакцией.
+// settings == nil - общие настройки не меняются
+func (r *PreferenceRepository) Update(
+	ctx context.Context,
+	settings *domain.DeliverySettings,
+	preferences []domain.NotificationPreference,
+) error {
+	now := time.Now().UTC()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if settings != nil {
+			settings.UpdatedAt = now
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"timezone", "quiet_hours_start", "quiet_hours_end", "digest_mode", "updated_at"}),
+			}).Create(settings).Error
+			if err != nil {
+				return fmt.Errorf("failed to save notification settings: %w", transient(err))
+			}
+		}
+
+		if len(preferences) == 0 {
+			return nil
+		}
+
+		for i := range preferences {
+			preferences[i].UpdatedAt = now
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).Create(&preferences).Error
+		if err != nil {
+			return fmt.Errorf("failed to save notification preferences: %w", transient(err))
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+const messagePreviewLength = 100
+
+// ChatNotificationUseCase создает in-app уведомление о новом сообщении в чате.
+// Письмо отправляется, только если получатель сейчас не в сети
+type ChatNotificationUseCase struct {
+	notificationRepo NotificationRepository
+	preferences      DeliveryPlanner
+}
+
+func NewChatNotificationUseCase(repo NotificationRepository, preferences DeliveryPlanner) *ChatNotificationUseCase {
+	return &ChatNotificationUseCase{
+		notificationRepo: repo,
+		preferences:      preferences,
+	}
+}
+
+func (uc *ChatNotificationUseCase) NotifyNewMessage(
+	ctx context.Context,
+	req model.ChatMessageNotificationRequest,
+) (*domain.Notification, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	senderName := singleLine(req.SenderName)
+	if senderName == "" {
+		senderName = "Пользователь"
+	}
+	preview := truncateText(req.Text, messagePreviewLength)
+
+	notification := &domain.Notification{
+		UserID:  req.UserID,
+		EventID: &req.EventID,
+		Type:    domain.TypeNewMessage,
+		Title:   fmt.Sprintf("Новое сообщение от %s", senderName),
+		Message: preview,
+		Metadata: domain.JSONB{
+			"chat_id":         req.ChatID,
+			"message_id":      req.MessageID,
+			"sender_id":       req.SenderID.String(),
+			"sender_name":     senderName,
+			"message_preview": preview,
+			"deep_link":       fmt.Sprintf("/chats/%s", req.ChatID),
+		},
+		SendAt: req.SendAt,
+	}
+
+	var channels []domain.DeliveryChannel
+	if !req.RecipientOnline && req.RecipientEmail != "" {
+		notification.Metadata["email"] = req.RecipientEmail
+		channels = append(channels, domain.ChannelEmail)
+	}
+
+	delivery, err := uc.preferences.PlanDelivery(ctx, notification, channels...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.notificationRepo.CreateWithOutbox(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func composeChatMessageEmail(
+	ctx context.Context,
+	render TemplatRender,
+	notification *domain.Notification,
+) (*domain.EmailMessage, error) {
+	email := metadataString(notification.Metadata, "email")
+	if email == "" {
+		return nil, domain.ErrMissingEmail
+	}
+
+	// имя отправителя попадает в тему письма, поэтому переводы строк убираем и у уже сохраненных уведомлений
+	senderName := singleLine(metadataString(notification.Metadata, "sender_name"))
+	templateData := TemplateData{
+		"SenderName":     senderName,
+		"MessagePreview": metadataString(notification.Metadata, "message_preview"),
+		"DeepLink":       metadataString(notification.Metadata, "deep_link"),
+	}
+
+	htmlBody, err := render.Render(ctx, "chat_message", templateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return &domain.EmailMessage{
+		To:       email,
+		Subject:  fmt.Sprintf("Новое сообщение от %s в АвиGo Маркетплейс", senderName),
+		HTMLBody: htmlBody,
+		TextBody: "",
+	}, nil
+}
+
+// singleLine схлопывает пробельные символы, включая переводы строк, в одиночные пробелы
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncateText обрезает текст до limit символов (не байт) по границе слова, если она близко
+func truncateText(text string, limit int) string {
+	text = singleLine(text)
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)[:limit]
+	if cut := strings.LastIndex(string(runes), " "); cut > len(string(runes))*3/4 {
+		return string(runes)[:cut] + "…"
+	}
+	return string(runes) + "…"
+}
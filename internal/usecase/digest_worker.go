@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type DigestRepository interface {
+	ClaimDueDigests(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Notification, error)
+	UpdateDigest(ctx context.Context, notifications []domain.Notification, outcomes []*domain.OutboxMessage) error
+}
+
+const inboxPath = "/notifications"
+
+// заголовки разделов сводки, тип без заголовка выводится как есть
+var digestSectionTitles = map[domain.NotificationType]string{
+	domain.TypeNewMessage:    "Сообщения",
+	domain.TypeListingUpdate: "Объявления",
+	domain.TypeNewReview:     "Отзывы",
+}
+
+// от кого уведомление: собеседник в чате, автор отзыва или объявление
+var digestSenderKeys = []string{"sender_name", "reviewer_name", "listing_title"}
+
+type digestItem struct {
+	Title    string
+	Message  string
+	DeepLink string
+}
+
+type digestSender struct {
+	Name  string
+	Items []digestItem
+}
+
+type digestSection struct {
+	Title   string
+	Count   int
+	Senders []*digestSender
+}
+
+// DigestWorker периодически собирает накопленные уведомления пользователей в режиме сводок
+// и отправляет каждому одно письмо. Уведомления сводки проходят тот же жизненный цикл, что и
+// отдельные письма: при временной ошибке сводка повторяется по RetryPolicy, при любой другой
+// или после исчерпания попыток уведомления помечаются failed
+type DigestWorker struct {
+	digestRepo     DigestRepository
+	emailSender    EmailSender
+	channels       ChannelFilter
+	templateRender TemplatRender
+	policy         RetryPolicy
+	pollInterval   time.Duration
+	batchSize      int
+	lockTimeout    time.Duration
+}
+
+// NewDigestWorker - policy задает повторы сводки отдельно от повторов одиночных писем
+func NewDigestWorker(
+	repo DigestRepository,
+	emailSender EmailSender,
+	channels ChannelFilter,
+	render TemplatRender,
+	policy RetryPolicy,
+	appBaseURL string,
+	cfg *config.DigestConfig,
+) *DigestWorker {
+	return &DigestWorker{
+		digestRepo:  repo,
+		emailSender: emailSender,
+		channels:    channels,
+		templateRender: &commonDataRender{
+			render: render,
+			common: TemplateData{"AppBaseURL": appBaseURL},
+		},
+		policy:       policy,
+		pollInterval: cfg.PollInterval,
+		batchSize:    cfg.BatchSize,
+		lockTimeout:  cfg.LockTimeout,
+	}
+}
+
+func (w *DigestWorker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Context cancelled, stopping digest worker...")
+			return nil
+		case <-ticker.C:
+			w.processDue(ctx)
+		}
+	}
+}
+
+func (w *DigestWorker) processDue(ctx context.Context) {
+	for {
+		notifications, err := w.digestRepo.ClaimDueDigests(ctx, time.Now().UTC(), w.lockTimeout, w.batchSize)
+		if err != nil {
+			log.Printf("Failed to claim digest notifications: %v", err)
+			return
+		}
+
+		users := groupByUser(notifications)
+		for userID, items := range users {
+			items = w.dropDisabled(ctx, items)
+			if len(items) == 0 {
+				continue
+			}
+
+			err := w.send(ctx, items)
+			if err != nil {
+				log.Printf("Failed to send digest to user %s: %v", userID, err)
+			} else {
+				log.Printf("Digest with %d notifications sent to user %s", len(items), userID)
+			}
+			w.complete(ctx, items, err)
+		}
+
+		if len(users) < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// dropDisabled завершает как skipped уведомления, для которых email отключили уже после постановки
+// в сводку, и возвращает остальные. Если настройки прочитать не удалось, повторяется вся сводка
+func (w *DigestWorker) dropDisabled(ctx context.Context, notifications []domain.Notification) []domain.Notification {
+	var allowed, disabled []domain.Notification
+	for _, notification := range notifications {
+		err := ensureChannelEnabled(ctx, w.channels, &notification, domain.ChannelEmail)
+		switch {
+		case err == nil:
+			allowed = append(allowed, notification)
+		case errors.Is(err, domain.ErrChannelDisabled):
+			disabled = append(disabled, notification)
+		default:
+			log.Printf("Failed to check preferences of user %s: %v", notification.UserID, err)
+			w.complete(ctx, notifications, err)
+			return nil
+		}
+	}
+
+	if len(disabled) > 0 {
+		w.skip(ctx, disabled)
+	}
+	return allowed
+}
+
+// skip сохраняет уведомления без отправки и без публикации итога: отказ от канала - не ошибка доставки
+func (w *DigestWorker) skip(ctx context.Context, notifications []domain.Notification) {
+	for i := range notifications {
+		if err := notifications[i].MarkSkipped(); err != nil {
+			log.Printf("Failed to change status of notification %s: %v", notifications[i].Id, err)
+			return
+		}
+	}
+
+	if err := w.digestRepo.UpdateDigest(ctx, notifications, nil); err != nil {
+		log.Printf("Failed to save skipped digest notifications: %v", err)
+	}
+}
+
+func (w *DigestWorker) send(ctx context.Context, notifications []domain.Notification) error {
+	msg, err := composeDigestEmail(ctx, w.templateRender, notifications)
+	if err != nil {
+		return err
+	}
+
+	if err := w.emailSender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// complete сохраняет итог сводки: sent с публикацией итога, повтор для временной ошибки
+// или окончательный failed
+func (w *DigestWorker) complete(ctx context.Context, notifications []domain.Notification, cause error) {
+	now := time.Now().UTC()
+	outcomes := make([]*domain.OutboxMessage, 0, len(notifications))
+
+	for i := range notifications {
+		notification := &notifications[i]
+
+		var err error
+		switch {
+		case cause == nil:
+			err = notification.MarkSent(now)
+			notification.DigestedAt = &now
+			outcomes = append(outcomes, domain.NewOutcomeMessage(domain.OutcomeSent, notification.Id, domain.ChannelEmail, now))
+		case domain.IsTransient(cause) && w.policy.CanRetry(notification.AttemptCount):
+			err = notification.MarkRetrying(cause, now.Add(w.policy.Delay(notification.AttemptCount)))
+		default:
+			err = notification.MarkFailed(cause, now)
+			outcomes = append(outcomes, domain.NewOutcomeMessage(domain.OutcomeFailed, notification.Id, domain.ChannelEmail, now))
+		}
+		if err != nil {
+			log.Printf("Failed to change status of notification %s: %v", notification.Id, err)
+			return
+		}
+	}
+
+	if err := w.digestRepo.UpdateDigest(ctx, notifications, outcomes); err != nil {
+		// аренда истечет, и сводка будет обработана повторно
+		log.Printf("Failed to save digest result: %v", err)
+	}
+}
+
+// groupByUser раскладывает уведомления по получателям в хронологическом порядке
+func groupByUser(notifications []domain.Notification) map[uuid.UUID][]domain.Notification {
+	slices.SortStableFunc(notifications, func(a, b domain.Notification) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	users := make(map[uuid.UUID][]domain.Notification)
+	for _, notification := range notifications {
+		users[notification.UserID] = append(users[notification.UserID], notification)
+	}
+	return users
+}
+
+// composeDigestEmail собирает сводку, сгруппированную по типу уведомления и отправителю.
+// Письмо уходит на адрес из самого свежего уведомления
+func composeDigestEmail(
+	ctx context.Context,
+	render TemplatRender,
+	notifications []domain.Notification,
+) (*domain.EmailMessage, error) {
+	var email string
+	for i := len(notifications) - 1; i >= 0 && email == ""; i-- {
+		email = metadataString(notifications[i].Metadata, "email")
+	}
+	if email == "" {
+		return nil, domain.ErrMissingEmail
+	}
+
+	var sections []*digestSection
+	for _, notification := range notifications {
+		title, ok := digestSectionTitles[notification.Type]
+		if !ok {
+			title = string(notification.Type)
+		}
+
+		i := slices.IndexFunc(sections, func(s *digestSection) bool { return s.Title == title })
+		if i < 0 {
+			sections = append(sections, &digestSection{Title: title})
+			i = len(sections) - 1
+		}
+		section := sections[i]
+
+		name := digestSenderName(notification.Metadata)
+		j := slices.IndexFunc(section.Senders, func(s *digestSender) bool { return s.Name == name })
+		if j < 0 {
+			section.Senders = append(section.Senders, &digestSender{Name: name})
+			j = len(section.Senders) - 1
+		}
+
+		section.Count++
+		section.Senders[j].Items = append(section.Senders[j].Items, digestItem{
+			Title:    notification.Title,
+			Message:  notification.Message,
+			DeepLink: metadataString(notification.Metadata, "deep_link"),
+		})
+	}
+
+	templateData := TemplateData{
+		"Total":     len(notifications),
+		"Sections":  sections,
+		"InboxLink": inboxPath,
+	}
+
+	htmlBody, err := render.Render(ctx, "digest", templateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return &domain.EmailMessage{
+		To:       email,
+		Subject:  fmt.Sprintf("Сводка уведомлений: %d новых — АвиGo Маркетплейс", len(notifications)),
+		HTMLBody: htmlBody,
+		TextBody: "",
+	}, nil
+}
+
+func digestSenderName(metadata domain.JSONB) string {
+	for _, key := range digestSenderKeys {
+		if name := metadataString(metadata, key); name != "" {
+			return name
+		}
+	}
+	return "АвиGo Маркетплейс"
+}
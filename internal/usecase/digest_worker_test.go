@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type digestRepoStub struct {
+	saved    []domain.Notification
+	outcomes []*domain.OutboxMessage
+}
+
+func (r *digestRepoStub) ClaimDueDigests(context.Context, time.Time, time.Duration, int) ([]domain.Notification, error) {
+	return nil, nil
+}
+
+func (r *digestRepoStub) UpdateDigest(_ context.Context, notifications []domain.Notification, outcomes []*domain.OutboxMessage) error {
+	r.saved = append(r.saved, notifications...)
+	r.outcomes = append(r.outcomes, outcomes...)
+	return nil
+}
+
+func claimedDigest(types ...domain.NotificationType) []domain.Notification {
+	userID := uuid.New()
+	notifications := make([]domain.Notification, 0, len(types))
+	for _, notificationType := range types {
+		notifications = append(notifications, domain.Notification{
+			Id:     uuid.New(),
+			UserID: userID,
+			Type:   notificationType,
+			Status: domain.StatusSending,
+		})
+	}
+	return notifications
+}
+
+func TestDigestWorkerDropDisabled(t *testing.T) {
+	repo := &digestRepoStub{}
+	filter := channelFilterStub{disabled: map[domain.NotificationType]bool{domain.TypeNewMessage: true}}
+	w := NewDigestWorker(repo, nil, filter, nil, RetryPolicy{MaxAttempts: 3}, "", &config.DigestConfig{RetryDelay: time.Minute})
+
+	items := claimedDigest(domain.TypeNewMessage, domain.TypeNewReview)
+	allowed := w.dropDisabled(context.Background(), items)
+
+	if len(allowed) != 1 || allowed[0].Type != domain.TypeNewReview {
+		t.Fatalf("allowed = %+v, want only the review", allowed)
+	}
+	if len(repo.saved) != 1 || repo.saved[0].Id != items[0].Id {
+		t.Fatalf("saved = %+v, want only the disabled message", repo.saved)
+	}
+	if repo.saved[0].Status != domain.StatusSkipped || repo.saved[0].LastError != "" {
+		t.Errorf("disabled notification status = %s, last error %q, want skipped without error",
+			repo.saved[0].Status, repo.saved[0].LastError)
+	}
+	if len(repo.outcomes) != 0 {
+		t.Errorf("outcomes = %+v, want none for a disabled channel", repo.outcomes)
+	}
+}
+
+func TestDigestWorkerDropDisabledRetriesOnLookupError(t *testing.T) {
+	repo := &digestRepoStub{}
+	filter := channelFilterStub{err: errors.New("connection refused")}
+	w := NewDigestWorker(repo, nil, filter, nil, RetryPolicy{MaxAttempts: 3}, "", &config.DigestConfig{RetryDelay: time.Minute})
+
+	items := claimedDigest(domain.TypeNewMessage, domain.TypeNewReview)
+	if allowed := w.dropDisabled(context.Background(), items); len(allowed) != 0 {
+		t.Fatalf("allowed = %+v, want none", allowed)
+	}
+
+	if len(repo.saved) != len(items) {
+		t.Fatalf("saved %d notifications, want %d", len(repo.saved), len(items))
+	}
+	for _, notification := range repo.saved {
+		if notification.Status != domain.StatusRetrying {
+			t.Errorf("notification %s status = %s, want %s", notification.Id, notification.Status, domain.StatusRetrying)
+		}
+	}
+	if len(repo.outcomes) != 0 {
+		t.Errorf("outcomes = %+v, want none for a retry", repo.outcomes)
+	}
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+// EmailComposer собирает письмо из сохраненного уведомления. Все данные для письма
+// должны лежать в Notification.Metadata, чтобы повторная попытка могла отрендерить его заново
+type EmailComposer func(ctx context.Context, render TemplatRender, notification *domain.Notification) (*domain.EmailMessage, error)
+
+// EmailDelivery выполняет попытку доставки уведомления по email и переводит его по статусам:
+// при временной ошибке (domain.TransientError) планирует повтор по RetryPolicy,
+// при любой другой окончательно помечает failed
+type EmailDelivery struct {
+	notificationRepo NotificationRepository
+	emailSender      EmailSender
+	channels         ChannelFilter
+	templateRender   TemplatRender
+	policy           RetryPolicy
+	composers        map[domain.NotificationType]EmailComposer
+}
+
+func NewEmailDelivery(
+	repo NotificationRepository,
+	emailSender EmailSender,
+	channels ChannelFilter,
+	render TemplatRender,
+	policy RetryPolicy,
+	appBaseURL string,
+) *EmailDelivery {
+	d := &EmailDelivery{
+		notificationRepo: repo,
+		emailSender:      emailSender,
+		channels:         channels,
+		templateRender: &commonDataRender{
+			render: render,
+			common: TemplateData{"AppBaseURL": appBaseURL},
+		},
+		policy:    policy,
+		composers: make(map[domain.NotificationType]EmailComposer),
+	}
+
+	d.RegisterComposer(domain.TypeEmailVerification, composeRegistrationEmail)
+	d.RegisterComposer(domain.TypeNewMessage, composeChatMessageEmail)
+	d.RegisterComposer(domain.TypeListingUpdate, composeTemplatedEmail)
+	d.RegisterComposer(domain.TypeNewReview, composeTemplatedEmail)
+	d.RegisterComposer(domain.TypeOrderCreated, composeTemplatedEmail)
+	d.RegisterComposer(domain.TypeOrderStatusChange, composeTemplatedEmail)
+
+	return d
+}
+
+// commonDataRender добавляет во все шаблоны общие данные (например, AppBaseURL для ссылок)
+type commonDataRender struct {
+	render TemplatRender
+	common TemplateData
+}
+
+func (r *commonDataRender) Render(ctx context.Context, templateName string, data TemplateData) (string, error) {
+	merged := make(TemplateData, len(r.common)+len(data))
+	for k, v := range r.common {
+		merged[k] = v
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	return r.render.Render(ctx, templateName, merged)
+}
+
+func (d *EmailDelivery) RegisterComposer(notificationType domain.NotificationType, composer EmailComposer) {
+	d.composers[notificationType] = composer
+}
+
+// Deliver начинает новую попытку доставки уведомления в статусе pending или retrying
+func (d *EmailDelivery) Deliver(ctx context.Context, notification *domain.Notification) error {
+	if err := notification.MarkSending(); err != nil {
+		return err
+	}
+	if err := d.notificationRepo.Update(ctx, notification); err != nil {
+		return fmt.Errorf("failed to update notification status: %w", err)
+	}
+
+	return d.attempt(ctx, notification)
+}
+
+// attempt отправляет письмо по уведомлению, уже переведенному в sending
+func (d *EmailDelivery) attempt(ctx context.Context, notification *domain.Notification) error {
+	if err := ensureChannelEnabled(ctx, d.channels, notification, domain.ChannelEmail); err != nil {
+		if errors.Is(err, domain.ErrChannelDisabled) {
+			return d.skip(ctx, notification)
+		}
+		return d.fail(ctx, notification, err)
+	}
+
+	msg, err := d.compose(ctx, notification)
+	if err != nil {
+		return d.fail(ctx, notification, err)
+	}
+
+	if err := d.emailSender.Send(ctx, msg); err != nil {
+		return d.fail(ctx, notification, fmt.Errorf("failed to send email: %w", err))
+	}
+
+	sentAt := time.Now().UTC()
+	if err := notification.MarkSent(sentAt); err != nil {
+		return err
+	}
+	outcome := domain.NewOutcomeMessage(domain.OutcomeSent, notification.Id, domain.ChannelEmail, sentAt)
+	if err := d.notificationRepo.UpdateWithOutcome(ctx, notification, outcome); err != nil {
+		// письмо уже ушло, повторять отправку из-за ошибки записи статуса нельзя
+		log.Printf("Failed to mark notification %s as sent: %v", notification.Id, err)
+	}
+
+	return nil
+}
+
+// ensureChannelEnabled возвращает domain.ErrChannelDisabled, если канал отключен,
+// ошибка чтения настроек временная
+func ensureChannelEnabled(
+	ctx context.Context,
+	filter ChannelFilter,
+	notification *domain.Notification,
+	channel domain.DeliveryChannel,
+) error {
+	enabled, err := filter.ChannelEnabled(ctx, notification.UserID, notification.Type, channel)
+	if err != nil {
+		return domain.NewTransientError(err)
+	}
+	if !enabled {
+		return domain.ErrChannelDisabled
+	}
+	return nil
+}
+
+func (d *EmailDelivery) compose(ctx context.Context, notification *domain.Notification) (*domain.EmailMessage, error) {
+	composer, ok := d.composers[notification.Type]
+	if !ok {
+		return nil, fmt.Errorf("no email composer for notification type %s", notification.Type)
+	}
+
+	msg, err := composer(ctx, d.templateRender, notification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose email: %w", err)
+	}
+
+	return msg, nil
+}
+
+// skip завершает уведомление без отправки: пользователь отключил канал, пока оно ждало доставки.
+// Итог не публикуется, это не ошибка доставки
+func (d *EmailDelivery) skip(ctx context.Context, notification *domain.Notification) error {
+	if err := notification.MarkSkipped(); err != nil {
+		return err
+	}
+	if err := d.notificationRepo.Update(ctx, notification); err != nil {
+		log.Printf("Failed to save status of notification %s: %v", notification.Id, err)
+	}
+
+	log.Printf("Email for notification %s skipped: channel disabled by user", notification.Id)
+	return nil
+}
+
+// fail планирует повтор для временных ошибок или окончательно помечает уведомление failed,
+// возвращает cause
+func (d *EmailDelivery) fail(ctx context.Context, notification *domain.Notification, cause error) error {
+	now := time.Now().UTC()
+
+	var err error
+	retrying := domain.IsTransient(cause) && d.policy.CanRetry(notification.AttemptCount)
+	if retrying {
+		nextAttemptAt := now.Add(d.policy.Delay(notification.AttemptCount))
+		err = notification.MarkRetrying(cause, nextAttemptAt)
+	} else {
+		err = notification.MarkFailed(cause, now)
+	}
+
+	if err != nil {
+		log.Printf("Failed to change status of notification %s: %v", notification.Id, err)
+		return cause
+	}
+
+	if retrying {
+		err = d.notificationRepo.Update(ctx, notification)
+	} else {
+		outcome := domain.NewOutcomeMessage(domain.OutcomeFailed, notification.Id, domain.ChannelEmail, now)
+		err = d.notificationRepo.UpdateWithOutcome(ctx, notification, outcome)
+	}
+	if err != nil {
+		log.Printf("Failed to save status of notification %s: %v", notification.Id, err)
+	}
+
+	return cause
+}
+
+// composeTemplatedEmail - общий сборщик письма для уведомлений, которые при создании
+// сохранили в Metadata шаблон (email_template) и тему (email_subject).
+// В шаблон передаются Title, Message, DeepLink и все метаданные в Meta
+func composeTemplatedEmail(
+	ctx context.Context,
+	render TemplatRender,
+	notification *domain.Notification,
+) (*domain.EmailMessage, error) {
+	email := metadataString(notification.Metadata, "email")
+	if email == "" {
+		return nil, domain.ErrMissingEmail
+	}
+
+	templateName := metadataString(notification.Metadata, "email_template")
+	if templateName == "" {
+		return nil, fmt.Errorf("email_template is missing in notification metadata")
+	}
+
+	subject := metadataString(notification.Metadata, "email_subject")
+	if subject == "" {
+		subject = notification.Title
+	}
+
+	templateData := TemplateData{
+		"Title":    notification.Title,
+		"Message":  notification.Message,
+		"DeepLink": metadataString(notification.Metadata, "deep_link"),
+		"Meta":     map[string]interface{}(notification.Metadata),
+	}
+
+	htmlBody, err := render.Render(ctx, templateName, templateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return &domain.EmailMessage{
+		To:       email,
+		Subject:  subject + " — АвиGo Маркетплейс",
+		HTMLBody: htmlBody,
+		TextBody: "",
+	}, nil
+}
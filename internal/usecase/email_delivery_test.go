@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type deliveryRepoStub struct {
+	NotificationRepository
+	updated  []domain.Notification
+	outcomes []*domain.OutboxMessage
+}
+
+func (r *deliveryRepoStub) Update(_ context.Context, notification *domain.Notification) error {
+	r.updated = append(r.updated, *notification)
+	return nil
+}
+
+func (r *deliveryRepoStub) UpdateWithOutcome(
+	_ context.Context,
+	notification *domain.Notification,
+	outcome *domain.OutboxMessage,
+) error {
+	r.updated = append(r.updated, *notification)
+	r.outcomes = append(r.outcomes, outcome)
+	return nil
+}
+
+type emailSenderStub struct {
+	sent []*domain.EmailMessage
+}
+
+func (s *emailSenderStub) Send(_ context.Context, message *domain.EmailMessage) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+type channelFilterStub struct {
+	disabled map[domain.NotificationType]bool
+	err      error
+}
+
+func (f channelFilterStub) ChannelEnabled(
+	_ context.Context,
+	_ uuid.UUID,
+	notificationType domain.NotificationType,
+	_ domain.DeliveryChannel,
+) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return !f.disabled[notificationType], nil
+}
+
+func TestEmailDeliverySkipsDisabledChannel(t *testing.T) {
+	repo := &deliveryRepoStub{}
+	sender := &emailSenderStub{}
+	filter := channelFilterStub{disabled: map[domain.NotificationType]bool{domain.TypeNewMessage: true}}
+	d := NewEmailDelivery(repo, sender, filter, nil, RetryPolicy{MaxAttempts: 3}, "")
+
+	notification := &domain.Notification{
+		Id:       uuid.New(),
+		UserID:   uuid.New(),
+		Type:     domain.TypeNewMessage,
+		Status:   domain.StatusPending,
+		Metadata: domain.JSONB{"email": "user@avigo.ru"},
+	}
+	if err := d.Deliver(context.Background(), notification); err != nil {
+		t.Fatalf("Deliver() error = %v, want nil", err)
+	}
+
+	if notification.Status != domain.StatusSkipped || notification.LastError != "" {
+		t.Errorf("status = %s, last error %q, want skipped without error", notification.Status, notification.LastError)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("sent %d emails, want none", len(sender.sent))
+	}
+	if len(repo.outcomes) != 0 {
+		t.Errorf("outcomes = %+v, want none for a disabled channel", repo.outcomes)
+	}
+	if last := repo.updated[len(repo.updated)-1]; last.Status != domain.StatusSkipped {
+		t.Errorf("saved status = %s, want %s", last.Status, domain.StatusSkipped)
+	}
+}
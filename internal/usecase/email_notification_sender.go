@@ -12,10 +12,20 @@ import (
 
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *domain.Notification) error
+	CreateWithOutbox(ctx context.Context, deliveries ...domain.PendingDelivery) error
+	ExistsByEventID(ctx context.Context, eventID string) (bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error)
+	GetVisibleByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error)
+	GetByIDForUser(ctx context.Context, id, userID uuid.UUID) (*domain.Notification, error)
 	GetByUserID(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.Notification, error)
+	GetByUserIDAfter(ctx context.Context, userID uuid.UUID, createdAt time.Time, id uuid.UUID, limit int) ([]domain.Notification, error)
 	Update(ctx context.Context, notification *domain.Notification) error
+	UpdateWithOutcome(ctx context.Context, notification *domain.Notification, outcome *domain.OutboxMessage) error
+	ClaimDueForRetry(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Notification, error)
+	ReleaseDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Notification, error)
+	CancelScheduledByEventID(ctx context.Context, eventID string, userID uuid.UUID) (int64, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
+	MarkAsReadForUser(ctx context.Context, id, userID uuid.UUID) error
 	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
@@ -31,19 +41,13 @@ type TemplatRender interface {
 
 type EmailNotificationUseCase struct {
 	notificationRepo NotificationRepository
-	emailSender      EmailSender
-	templateRender   TemplatRender
+	preferences      DeliveryPlanner
 }
 
-func NewEmailNotificationUseCase(
-	repo NotificationRepository,
-	emailSender EmailSender,
-	render TemplatRender,
-) *EmailNotificationUseCase {
+func NewEmailNotificationUseCase(repo NotificationRepository, preferences DeliveryPlanner) *EmailNotificationUseCase {
 	return &EmailNotificationUseCase{
 		notificationRepo: repo,
-		emailSender:      emailSender,
-		templateRender:   render,
+		preferences:      preferences,
 	}
 }
 
@@ -54,13 +58,14 @@ func (uc *EmailNotificationUseCase) SendRegistrationEmail(
 
 	if err := req.Validate(); err != nil {
 		return &model.SendEmailNotificationResponse{
-			Status: "failed",
+			Status: string(domain.StatusFailed),
 			Error:  fmt.Errorf("validation failed: %w", err),
 		}, err
 	}
 
 	notification := &domain.Notification{
 		UserID:  req.UserID,
+		EventID: &req.EventID,
 		Type:    domain.TypeEmailVerification,
 		Title:   "Подтверждение регистрации",
 		Message: fmt.Sprintf("Ваш код подтверждения: %s", req.ConfirmationCode),
@@ -70,53 +75,70 @@ func (uc *EmailNotificationUseCase) SendRegistrationEmail(
 			"display_name":      req.DisplayName,
 			"expires_at":        req.ExpiresAt.Format(time.RFC3339),
 		},
-		Status: domain.StatusPending,
 	}
 
-	if err := uc.notificationRepo.Create(ctx, notification); err != nil {
+	// письмо отправит OutboxDispatcher: уведомление и задача доставки сохраняются атомарно
+	delivery, err := uc.preferences.PlanDelivery(ctx, notification, domain.ChannelEmail)
+	if err != nil {
 		return &model.SendEmailNotificationResponse{
-			Status: "failed",
+			Status: string(domain.StatusFailed),
+			Error:  err,
+		}, err
+	}
+
+	if err := uc.notificationRepo.CreateWithOutbox(ctx, delivery); err != nil {
+		return &model.SendEmailNotificationResponse{
+			Status: string(domain.StatusFailed),
 			Error:  fmt.Errorf("failed to create notification: %w", err),
 		}, err
 	}
 
-	expiresIn := time.Until(req.ExpiresAt).Minutes()
-	templateData := TemplateData{
-		"DisplayName":      req.DisplayName,
-		"ConfirmationCode": req.ConfirmationCode,
-		"ExpiresIn":        fmt.Sprintf("%.0f минут", expiresIn),
+	return &model.SendEmailNotificationResponse{
+		NotificationID: notification.Id,
+		Status:         string(notification.Status),
+		Error:          nil,
+	}, nil
+}
+
+func composeRegistrationEmail(
+	ctx context.Context,
+	render TemplatRender,
+	notification *domain.Notification,
+) (*domain.EmailMessage, error) {
+	email := metadataString(notification.Metadata, "email")
+	if email == "" {
+		return nil, domain.ErrMissingEmail
 	}
 
-	htmlBody, err := uc.templateRender.Render(ctx, "registration", templateData)
+	expiresAt, err := time.Parse(time.RFC3339, metadataString(notification.Metadata, "expires_at"))
 	if err != nil {
-		_ = uc.notificationRepo.Update(ctx, notification)
-		return &model.SendEmailNotificationResponse{
-			NotificationID: notification.Id,
-			Status:         "failde",
-			Error:          fmt.Errorf("failed to render template: %w", err),
-		}, err
+		return nil, fmt.Errorf("invalid expires_at: %w", err)
+	}
+	// просроченный код отправлять бессмысленно, пользователь запросит новый
+	if expiresAt.Before(time.Now()) {
+		return nil, domain.ErrExpiredCode
 	}
 
-	emailMsg := &domain.EmailMessage{
-		To:       req.Email,
-		Subject:  "Подтверждение регистрации в АвиGo Маркетплейс",
-		HTMLBody: htmlBody,
-		TextBody: "",
+	templateData := TemplateData{
+		"DisplayName":      metadataString(notification.Metadata, "display_name"),
+		"ConfirmationCode": metadataString(notification.Metadata, "confirmation_code"),
+		"ExpiresIn":        fmt.Sprintf("%.0f минут", time.Until(expiresAt).Minutes()),
 	}
 
-	if err := uc.emailSender.Send(ctx, emailMsg); err != nil {
-		_ = uc.notificationRepo.Update(ctx, notification)
-		return &model.SendEmailNotificationResponse{
-			NotificationID: notification.Id,
-			Status:         "failed",
-			Error:          fmt.Errorf("failed to send email: %w", err),
-		}, err
+	htmlBody, err := render.Render(ctx, "registration", templateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
 	}
 
-	return &model.SendEmailNotificationResponse{
-		NotificationID: notification.Id,
-		Status:         "sent",
-		SentAt:         time.Now(),
-		Error:          nil,
+	return &domain.EmailMessage{
+		To:       email,
+		Subject:  "Подтверждение регистрации в АвиGo Маркетплейс",
+		HTMLBody: htmlBody,
+		TextBody: "",
 	}, nil
 }
+
+func metadataString(metadata domain.JSONB, key string) string {
+	value, _ := metadata[key].(string)
+	return value
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+)
+
+// EventDeduplicator отвечает на вопрос, обработано ли уже событие с таким event_id.
+// Kafka гарантирует доставку at-least-once, поэтому повторы - штатная ситуация
+type EventDeduplicator struct {
+	notificationRepo NotificationRepository
+}
+
+func NewEventDeduplicator(repo NotificationRepository) *EventDeduplicator {
+	return &EventDeduplicator{
+		notificationRepo: repo,
+	}
+}
+
+func (d *EventDeduplicator) IsProcessed(ctx context.Context, eventID string) (bool, error) {
+	processed, err := d.notificationRepo.ExistsByEventID(ctx, eventID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed event: %w", err)
+	}
+
+	return processed, nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+// InboxUseCase - чтение in-app уведомлений пользователя для REST API.
+// Все методы работают от имени domain.Principal из контекста: обычный пользователь
+// ограничен своими уведомлениями, администратор может читать любые
+type InboxUseCase struct {
+	notificationRepo NotificationRepository
+}
+
+func NewInboxUseCase(repo NotificationRepository) *InboxUseCase {
+	return &InboxUseCase{
+		notificationRepo: repo,
+	}
+}
+
+func (uc *InboxUseCase) ListNotifications(
+	ctx context.Context,
+	req model.ListNotificationsRequest,
+) ([]domain.Notification, error) {
+	userID, err := resolveUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	req.UserID = userID
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	notifications, err := uc.notificationRepo.GetByUserID(ctx, req.UserID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// GetNotification не возвращает отложенные уведомления до выпуска, в том числе администратору
+func (uc *InboxUseCase) GetNotification(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	var (
+		notification *domain.Notification
+		err          error
+	)
+	if principal.IsAdmin {
+		notification, err = uc.notificationRepo.GetVisibleByID(ctx, id)
+	} else {
+		notification, err = uc.notificationRepo.GetByIDForUser(ctx, id, principal.UserID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+// MarkAsRead идемпотентна: повторная отметка уже прочитанного уведомления не считается ошибкой.
+// notification.read публикуется через outbox, запись о нем создается вместе с отметкой о прочтении
+func (uc *InboxUseCase) MarkAsRead(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	notification, err := uc.GetNotification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	if principal.IsAdmin {
+		err = uc.notificationRepo.MarkAsRead(ctx, id)
+	} else {
+		err = uc.notificationRepo.MarkAsReadForUser(ctx, id, principal.UserID)
+	}
+	// ErrNotificationNotFound после успешного чтения - параллельный запрос уже отметил прочтение,
+	// повторное чтение ниже вернет актуальное состояние
+	if err != nil && !errors.Is(err, domain.ErrNotificationNotFound) {
+		return nil, fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	return uc.GetNotification(ctx, id)
+}
+
+func (uc *InboxUseCase) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	userID, err := resolveUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := uc.notificationRepo.CountUnreadByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// ResolveStreamUser определяет, чьи уведомления будет получать SSE подключение
+func (uc *InboxUseCase) ResolveStreamUser(ctx context.Context, requested uuid.UUID) (uuid.UUID, error) {
+	return resolveUserID(ctx, requested)
+}
+
+// ListMissed возвращает уведомления, созданные после lastEventID, для возобновления SSE потока
+func (uc *InboxUseCase) ListMissed(
+	ctx context.Context,
+	userID uuid.UUID,
+	lastEventID uuid.UUID,
+) ([]domain.Notification, error) {
+	last, err := uc.GetNotification(ctx, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if last.UserID != userID {
+		return nil, domain.ErrNotificationNotFound
+	}
+
+	notifications, err := uc.notificationRepo.GetByUserIDAfter(
+		ctx, userID, last.CreatedAt, last.Id, model.MaxNotificationsLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list missed notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// resolveUserID подставляет пользователя из токена, если user_id не передан явно,
+// и запрещает обращаться к чужим уведомлениям всем, кроме администратора
+func resolveUserID(ctx context.Context, requested uuid.UUID) (uuid.UUID, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return uuid.Nil, domain.ErrUnauthenticated
+	}
+
+	if requested == uuid.Nil {
+		return principal.UserID, nil
+	}
+
+	if !principal.CanAccessUser(requested) {
+		return uuid.Nil, domain.ErrForbidden
+	}
+
+	return requested, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+// concurrentReadRepo имитирует гонку: между чтением уведомления и отметкой о прочтении
+// его успевает прочитать параллельный запрос
+type concurrentReadRepo struct {
+	NotificationRepository
+	notification domain.Notification
+	markErr      error
+}
+
+func (r *concurrentReadRepo) GetByIDForUser(_ context.Context, id, userID uuid.UUID) (*domain.Notification, error) {
+	if id != r.notification.Id || userID != r.notification.UserID {
+		return nil, domain.ErrNotificationNotFound
+	}
+	notification := r.notification
+	return &notification, nil
+}
+
+func (r *concurrentReadRepo) MarkAsReadForUser(context.Context, uuid.UUID, uuid.UUID) error {
+	readAt := time.Now().UTC()
+	r.notification.ReadAt = &readAt
+	return r.markErr
+}
+
+func TestMarkAsReadIsIdempotentUnderConcurrentRead(t *testing.T) {
+	userID := uuid.New()
+	repo := &concurrentReadRepo{
+		notification: domain.Notification{Id: uuid.New(), UserID: userID, Status: domain.StatusSent},
+		markErr:      domain.ErrNotificationNotFound,
+	}
+	uc := NewInboxUseCase(repo)
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: userID})
+
+	notification, err := uc.MarkAsRead(ctx, repo.notification.Id)
+	if err != nil {
+		t.Fatalf("MarkAsRead() error = %v, want nil", err)
+	}
+	if notification.ReadAt == nil {
+		t.Error("MarkAsRead() returned notification without read_at")
+	}
+}
+
+func TestMarkAsReadReturnsRepositoryErrors(t *testing.T) {
+	userID := uuid.New()
+	markErr := errors.New("connection reset")
+	repo := &concurrentReadRepo{
+		notification: domain.Notification{Id: uuid.New(), UserID: userID, Status: domain.StatusSent},
+		markErr:      markErr,
+	}
+	uc := NewInboxUseCase(repo)
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: userID})
+
+	if _, err := uc.MarkAsRead(ctx, repo.notification.Id); !errors.Is(err, markErr) {
+		t.Errorf("MarkAsRead() error = %v, want %v", err, markErr)
+	}
+}
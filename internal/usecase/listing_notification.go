@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+// ListingNotificationUseCase уведомляет об изменениях объявления: цена, статус, отказ модерации.
+// Для каждого вида изменения свои заголовок, текст и шаблон письма
+type ListingNotificationUseCase struct {
+	notificationRepo NotificationRepository
+	preferences      DeliveryPlanner
+}
+
+func NewListingNotificationUseCase(repo NotificationRepository, preferences DeliveryPlanner) *ListingNotificationUseCase {
+	return &ListingNotificationUseCase{
+		notificationRepo: repo,
+		preferences:      preferences,
+	}
+}
+
+type listingChangeContent struct {
+	title    string
+	message  string
+	template string
+	deepLink string
+}
+
+func (uc *ListingNotificationUseCase) NotifyListingUpdate(
+	ctx context.Context,
+	req model.ListingUpdateNotificationRequest,
+) (*domain.Notification, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	content := describeListingChange(req)
+
+	notification := &domain.Notification{
+		UserID:  req.UserID,
+		EventID: &req.EventID,
+		Type:    domain.TypeListingUpdate,
+		Title:   content.title,
+		Message: content.message,
+		Metadata: domain.JSONB{
+			"listing_id":     req.ListingID,
+			"listing_title":  req.ListingTitle,
+			"change_kind":    string(req.ChangeKind),
+			"deep_link":      content.deepLink,
+			"email_template": content.template,
+			"email_subject":  content.title,
+		},
+		SendAt: req.SendAt,
+	}
+
+	switch req.ChangeKind {
+	case domain.ListingPriceChanged:
+		notification.Metadata["old_price"] = formatPrice(req.OldPrice, req.Currency)
+		notification.Metadata["new_price"] = formatPrice(req.NewPrice, req.Currency)
+	case domain.ListingStatusChanged:
+		notification.Metadata["new_status"] = string(req.NewStatus)
+	case domain.ListingModerationRejected:
+		notification.Metadata["reason"] = req.Reason
+	}
+
+	var channels []domain.DeliveryChannel
+	if req.RecipientEmail != "" {
+		notification.Metadata["email"] = req.RecipientEmail
+		channels = append(channels, domain.ChannelEmail)
+	}
+
+	delivery, err := uc.preferences.PlanDelivery(ctx, notification, channels...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.notificationRepo.CreateWithOutbox(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func describeListingChange(req model.ListingUpdateNotificationRequest) listingChangeContent {
+	listingLink := fmt.Sprintf("/listings/%s", req.ListingID)
+	title := req.ListingTitle
+	if title == "" {
+		title = "ваше объявление"
+	}
+
+	switch req.ChangeKind {
+	case domain.ListingPriceChanged:
+		return listingChangeContent{
+			title: "Цена изменилась",
+			message: fmt.Sprintf("Цена на «%s» изменилась: %s → %s",
+				title, formatPrice(req.OldPrice, req.Currency), formatPrice(req.NewPrice, req.Currency)),
+			template: "listing_price_changed",
+			deepLink: listingLink,
+		}
+	case domain.ListingModerationRejected:
+		message := fmt.Sprintf("Объявление «%s» не прошло модерацию", title)
+		if req.Reason != "" {
+			message += ": " + req.Reason
+		}
+		return listingChangeContent{
+			title:    "Объявление отклонено модерацией",
+			message:  message,
+			template: "listing_moderation_rejected",
+			deepLink: listingLink + "/edit",
+		}
+	}
+
+	content := listingChangeContent{
+		template: "listing_status_changed",
+		deepLink: listingLink,
+	}
+	switch req.NewStatus {
+	case domain.ListingStatusSold:
+		content.title = "Объявление продано"
+		content.message = fmt.Sprintf("Объявление «%s» отмечено как проданное", title)
+	case domain.ListingStatusArchived:
+		content.title = "Объявление перенесено в архив"
+		content.message = fmt.Sprintf("Объявление «%s» больше не отображается в поиске", title)
+	case domain.ListingStatusModerated:
+		content.title = "Объявление опубликовано"
+		content.message = fmt.Sprintf("Объявление «%s» прошло модерацию и опубликовано", title)
+	}
+	return content
+}
+
+func formatPrice(amount float64, currency string) string {
+	if currency == "" {
+		currency = "RUB"
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64) + " " + currency
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+func TestDescribeListingChange(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.ListingUpdateNotificationRequest
+		want listingChangeContent
+	}{
+		{
+			name: "price changed",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:    "42",
+				ListingTitle: "Велосипед",
+				ChangeKind:   domain.ListingPriceChanged,
+				OldPrice:     15000,
+				NewPrice:     12500.5,
+				Currency:     "USD",
+			},
+			want: listingChangeContent{
+				title:    "Цена изменилась",
+				message:  "Цена на «Велосипед» изменилась: 15000 USD → 12500.5 USD",
+				template: "listing_price_changed",
+				deepLink: "/listings/42",
+			},
+		},
+		{
+			name: "price changed without currency and title",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:  "42",
+				ChangeKind: domain.ListingPriceChanged,
+				OldPrice:   100,
+				NewPrice:   90,
+			},
+			want: listingChangeContent{
+				title:    "Цена изменилась",
+				message:  "Цена на «ваше объявление» изменилась: 100 RUB → 90 RUB",
+				template: "listing_price_changed",
+				deepLink: "/listings/42",
+			},
+		},
+		{
+			name: "moderation rejected with reason",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:    "42",
+				ListingTitle: "Велосипед",
+				ChangeKind:   domain.ListingModerationRejected,
+				Reason:       "запрещенный товар",
+			},
+			want: listingChangeContent{
+				title:    "Объявление отклонено модерацией",
+				message:  "Объявление «Велосипед» не прошло модерацию: запрещенный товар",
+				template: "listing_moderation_rejected",
+				deepLink: "/listings/42/edit",
+			},
+		},
+		{
+			name: "moderation rejected without reason",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:    "42",
+				ListingTitle: "Велосипед",
+				ChangeKind:   domain.ListingModerationRejected,
+			},
+			want: listingChangeContent{
+				title:    "Объявление отклонено модерацией",
+				message:  "Объявление «Велосипед» не прошло модерацию",
+				template: "listing_moderation_rejected",
+				deepLink: "/listings/42/edit",
+			},
+		},
+		{
+			name: "status sold",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:    "42",
+				ListingTitle: "Велосипед",
+				ChangeKind:   domain.ListingStatusChanged,
+				NewStatus:    domain.ListingStatusSold,
+			},
+			want: listingChangeContent{
+				title:    "Объявление продано",
+				message:  "Объявление «Велосипед» отмечено как проданное",
+				template: "listing_status_changed",
+				deepLink: "/listings/42",
+			},
+		},
+		{
+			name: "status archived",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:    "42",
+				ListingTitle: "Велосипед",
+				ChangeKind:   domain.ListingStatusChanged,
+				NewStatus:    domain.ListingStatusArchived,
+			},
+			want: listingChangeContent{
+				title:    "Объявление перенесено в архив",
+				message:  "Объявление «Велосипед» больше не отображается в поиске",
+				template: "listing_status_changed",
+				deepLink: "/listings/42",
+			},
+		},
+		{
+			name: "status moderated",
+			req: model.ListingUpdateNotificationRequest{
+				ListingID:    "42",
+				ListingTitle: "Велосипед",
+				ChangeKind:   domain.ListingStatusChanged,
+				NewStatus:    domain.ListingStatusModerated,
+			},
+			want: listingChangeContent{
+				title:    "Объявление опубликовано",
+				message:  "Объявление «Велосипед» прошло модерацию и опубликовано",
+				template: "listing_status_changed",
+				deepLink: "/listings/42",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.EventID = "evt-1"
+			tt.req.UserID = uuid.New()
+			if err := tt.req.Validate(); err != nil {
+				t.Fatalf("request must be valid: %v", err)
+			}
+
+			if got := describeListingChange(tt.req); got != tt.want {
+				t.Errorf("describeListingChange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{amount: 100, want: "100 RUB"},
+		{amount: 99.99, currency: "EUR", want: "99.99 EUR"},
+		{amount: 0, currency: "USD", want: "0 USD"},
+		{amount: 1500000, want: "1500000 RUB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatPrice(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("formatPrice(%v, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+// NotificationScheduler выпускает отложенные уведомления, у которых наступил send_at,
+// и отменяет еще не выпущенные по событию продюсера. Выпущенное уведомление
+// доставляется по обычному пути: задачи outbox уже созданы вместе с ним
+type NotificationScheduler struct {
+	notificationRepo NotificationRepository
+	pollInterval     time.Duration
+	batchSize        int
+}
+
+func NewNotificationScheduler(repo NotificationRepository, cfg *config.SchedulerConfig) *NotificationScheduler {
+	return &NotificationScheduler{
+		notificationRepo: repo,
+		pollInterval:     cfg.PollInterval,
+		batchSize:        cfg.BatchSize,
+	}
+}
+
+func (s *NotificationScheduler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Context cancelled, stopping notification scheduler...")
+			return nil
+		case <-ticker.C:
+			s.releaseDue(ctx)
+		}
+	}
+}
+
+func (s *NotificationScheduler) releaseDue(ctx context.Context) {
+	for {
+		notifications, err := s.notificationRepo.ReleaseDueScheduled(ctx, time.Now().UTC(), s.batchSize)
+		if err != nil {
+			log.Printf("Failed to release scheduled notifications: %v", err)
+			return
+		}
+
+		for _, notification := range notifications {
+			log.Printf("Scheduled notification %s released (status: %s)", notification.Id, notification.Status)
+		}
+
+		if len(notifications) < s.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// CancelScheduled отменяет отложенные уведомления события. Уже выпущенные уведомления не трогаются,
+// поэтому повторная или запоздавшая отмена не считается ошибкой, а отмена, опередившая само
+// событие, применится при его обработке
+func (s *NotificationScheduler) CancelScheduled(ctx context.Context, req model.CancelScheduledRequest) (int64, error) {
+	if err := req.Validate(); err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+
+	cancelled, err := s.notificationRepo.CancelScheduledByEventID(ctx, req.TargetEventID, req.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel scheduled notifications: %w", err)
+	}
+
+	return cancelled, nil
+}
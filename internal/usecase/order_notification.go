@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+// OrderNotificationUseCase уведомляет обе стороны заказа: покупателя и продавца.
+// Оба уведомления создаются в одной транзакции, чтобы повтор события не породил только одно из них
+type OrderNotificationUseCase struct {
+	notificationRepo NotificationRepository
+	preferences      DeliveryPlanner
+}
+
+func NewOrderNotificationUseCase(repo NotificationRepository, preferences DeliveryPlanner) *OrderNotificationUseCase {
+	return &OrderNotificationUseCase{
+		notificationRepo: repo,
+		preferences:      preferences,
+	}
+}
+
+func (uc *OrderNotificationUseCase) NotifyOrderCreated(
+	ctx context.Context,
+	req model.OrderNotificationRequest,
+) ([]*domain.Notification, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return uc.notifyParticipants(ctx, domain.TypeOrderCreated, "order_created", req)
+}
+
+func (uc *OrderNotificationUseCase) NotifyOrderStatusChanged(
+	ctx context.Context,
+	req model.OrderNotificationRequest,
+) ([]*domain.Notification, error) {
+	if err := req.ValidateStatusChange(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return uc.notifyParticipants(ctx, domain.TypeOrderStatusChange, "order_"+string(req.Status), req)
+}
+
+func (uc *OrderNotificationUseCase) notifyParticipants(
+	ctx context.Context,
+	notificationType domain.NotificationType,
+	template string,
+	req model.OrderNotificationRequest,
+) ([]*domain.Notification, error) {
+	buyer := newOrderNotification(notificationType, template, domain.OrderRoleBuyer, req)
+	seller := newOrderNotification(notificationType, template, domain.OrderRoleSeller, req)
+
+	buyerDelivery, err := uc.orderDelivery(ctx, buyer, req.Buyer.Email)
+	if err != nil {
+		return nil, err
+	}
+	sellerDelivery, err := uc.orderDelivery(ctx, seller, req.Seller.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.notificationRepo.CreateWithOutbox(ctx, buyerDelivery, sellerDelivery); err != nil {
+		return nil, fmt.Errorf("failed to create notifications: %w", err)
+	}
+
+	return []*domain.Notification{buyer, seller}, nil
+}
+
+func newOrderNotification(
+	notificationType domain.NotificationType,
+	template string,
+	role domain.OrderRole,
+	req model.OrderNotificationRequest,
+) *domain.Notification {
+	recipient, counterparty := req.Buyer, req.Seller
+	if role == domain.OrderRoleSeller {
+		recipient, counterparty = req.Seller, req.Buyer
+	}
+
+	title, message := describeOrderEvent(role, req)
+
+	metadata := domain.JSONB{
+		"order_id":          req.OrderID,
+		"listing_id":        req.ListingID,
+		"listing_title":     req.ListingTitle,
+		"role":              string(role),
+		"counterparty_id":   counterparty.UserID.String(),
+		"counterparty_name": counterparty.Name,
+		"amount":            formatPrice(req.Amount, req.Currency),
+		"deep_link":         fmt.Sprintf("/orders/%s", req.OrderID),
+		"email_template":    template,
+		"email_subject":     title,
+	}
+	if req.Status != "" {
+		metadata["order_status"] = string(req.Status)
+	}
+	if req.TrackingNumber != "" {
+		metadata["tracking_number"] = req.TrackingNumber
+	}
+	if req.Reason != "" {
+		metadata["reason"] = req.Reason
+	}
+
+	return &domain.Notification{
+		UserID:   recipient.UserID,
+		EventID:  &req.EventID,
+		Type:     notificationType,
+		Title:    title,
+		Message:  message,
+		Metadata: metadata,
+		SendAt:   req.SendAt,
+	}
+}
+
+// orderDelivery учитывает настройки каждого участника отдельно: покупатель может отключить
+// письма о заказах, не затрагивая продавца
+func (uc *OrderNotificationUseCase) orderDelivery(
+	ctx context.Context,
+	notification *domain.Notification,
+	email string,
+) (domain.PendingDelivery, error) {
+	if email == "" {
+		return domain.NewPendingDelivery(notification), nil
+	}
+
+	notification.Metadata["email"] = email
+	return uc.preferences.PlanDelivery(ctx, notification, domain.ChannelEmail)
+}
+
+// describeOrderEvent возвращает заголовок и текст уведомления с учетом роли получателя
+func describeOrderEvent(role domain.OrderRole, req model.OrderNotificationRequest) (string, string) {
+	listing := req.ListingTitle
+	if listing == "" {
+		listing = req.OrderID
+	}
+	buyerName := req.Buyer.Name
+	if buyerName == "" {
+		buyerName = "Покупатель"
+	}
+	amount := formatPrice(req.Amount, req.Currency)
+	isBuyer := role == domain.OrderRoleBuyer
+
+	switch req.Status {
+	case "":
+		if isBuyer {
+			return "Заказ оформлен", fmt.Sprintf("Вы оформили заказ «%s» на сумму %s", listing, amount)
+		}
+		return "Новый заказ", fmt.Sprintf("%s оформил(а) заказ «%s» на сумму %s", buyerName, listing, amount)
+
+	case domain.OrderStatusPaid:
+		if isBuyer {
+			return "Заказ оплачен", fmt.Sprintf("Оплата заказа «%s» прошла успешно, продавец готовит отправку", listing)
+		}
+		return "Заказ оплачен", fmt.Sprintf("%s оплатил(а) заказ «%s», отправьте товар покупателю", buyerName, listing)
+
+	case domain.OrderStatusShipped:
+		message := fmt.Sprintf("Вы отметили заказ «%s» как отправленный", listing)
+		if isBuyer {
+			message = fmt.Sprintf("Продавец отправил заказ «%s»", listing)
+		}
+		if req.TrackingNumber != "" {
+			message += fmt.Sprintf(", трек-номер %s", req.TrackingNumber)
+		}
+		return "Заказ отправлен", message
+
+	case domain.OrderStatusDelivered:
+		if isBuyer {
+			return "Заказ доставлен", fmt.Sprintf("Заказ «%s» доставлен. Не забудьте оставить отзыв о продавце", listing)
+		}
+		return "Заказ доставлен", fmt.Sprintf("Заказ «%s» доставлен покупателю %s", listing, buyerName)
+
+	case domain.OrderStatusCancelled:
+		message := fmt.Sprintf("Заказ «%s» отменен", listing)
+		if req.Reason != "" {
+			message += ": " + req.Reason
+		}
+		if isBuyer {
+			message += ". Если заказ был оплачен, деньги вернутся на карту"
+		}
+		return "Заказ отменен", message
+
+	case domain.OrderStatusDisputed:
+		// событие не сообщает, кто открыл спор: покупатель, продавец или поддержка
+		return "Открыт спор по заказу", fmt.Sprintf("По заказу «%s» открыт спор, поддержка свяжется с вами", listing)
+	}
+
+	return "Заказ обновлен", fmt.Sprintf("Статус заказа «%s» изменился", listing)
+}
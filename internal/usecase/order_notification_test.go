@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+func testOrderRequest(status domain.OrderStatus) model.OrderNotificationRequest {
+	return model.OrderNotificationRequest{
+		EventID:      "evt-1",
+		OrderID:      "order-1",
+		ListingTitle: "Велосипед",
+		Buyer:        model.OrderParticipant{UserID: uuid.New(), Name: "Анна"},
+		Seller:       model.OrderParticipant{UserID: uuid.New(), Name: "Иван"},
+		Amount:       1500,
+		Currency:     "RUB",
+		Status:       status,
+	}
+}
+
+func TestDescribeOrderEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        domain.OrderRole
+		mutate      func(req *model.OrderNotificationRequest)
+		status      domain.OrderStatus
+		wantTitle   string
+		wantMessage string
+	}{
+		{
+			name:        "created for buyer",
+			role:        domain.OrderRoleBuyer,
+			wantTitle:   "Заказ оформлен",
+			wantMessage: "Вы оформили заказ «Велосипед» на сумму 1500 RUB",
+		},
+		{
+			name:        "created for seller",
+			role:        domain.OrderRoleSeller,
+			wantTitle:   "Новый заказ",
+			wantMessage: "Анна оформил(а) заказ «Велосипед» на сумму 1500 RUB",
+		},
+		{
+			name:        "created without listing title and buyer name",
+			role:        domain.OrderRoleSeller,
+			mutate:      func(req *model.OrderNotificationRequest) { req.ListingTitle, req.Buyer.Name = "", "" },
+			wantTitle:   "Новый заказ",
+			wantMessage: "Покупатель оформил(а) заказ «order-1» на сумму 1500 RUB",
+		},
+		{
+			name:        "paid for buyer",
+			role:        domain.OrderRoleBuyer,
+			status:      domain.OrderStatusPaid,
+			wantTitle:   "Заказ оплачен",
+			wantMessage: "Оплата заказа «Велосипед» прошла успешно",
+		},
+		{
+			name:        "paid for seller",
+			role:        domain.OrderRoleSeller,
+			status:      domain.OrderStatusPaid,
+			wantTitle:   "Заказ оплачен",
+			wantMessage: "Анна оплатил(а) заказ «Велосипед», отправьте товар покупателю",
+		},
+		{
+			name:        "shipped for buyer with tracking number",
+			role:        domain.OrderRoleBuyer,
+			status:      domain.OrderStatusShipped,
+			mutate:      func(req *model.OrderNotificationRequest) { req.TrackingNumber = "RA123" },
+			wantTitle:   "Заказ отправлен",
+			wantMessage: "Продавец отправил заказ «Велосипед», трек-номер RA123",
+		},
+		{
+			name:        "shipped for seller",
+			role:        domain.OrderRoleSeller,
+			status:      domain.OrderStatusShipped,
+			wantTitle:   "Заказ отправлен",
+			wantMessage: "Вы отметили заказ «Велосипед» как отправленный",
+		},
+		{
+			name:        "delivered for buyer",
+			role:        domain.OrderRoleBuyer,
+			status:      domain.OrderStatusDelivered,
+			wantTitle:   "Заказ доставлен",
+			wantMessage: "Не забудьте оставить отзыв о продавце",
+		},
+		{
+			name:        "delivered for seller",
+			role:        domain.OrderRoleSeller,
+			status:      domain.OrderStatusDelivered,
+			wantTitle:   "Заказ доставлен",
+			wantMessage: "Заказ «Велосипед» доставлен покупателю Анна",
+		},
+		{
+			name:        "cancelled for buyer with reason",
+			role:        domain.OrderRoleBuyer,
+			status:      domain.OrderStatusCancelled,
+			mutate:      func(req *model.OrderNotificationRequest) { req.Reason = "нет в наличии" },
+			wantTitle:   "Заказ отменен",
+			wantMessage: "Заказ «Велосипед» отменен: нет в наличии. Если заказ был оплачен, деньги вернутся на карту",
+		},
+		{
+			name:        "cancelled for seller",
+			role:        domain.OrderRoleSeller,
+			status:      domain.OrderStatusCancelled,
+			wantTitle:   "Заказ отменен",
+			wantMessage: "Заказ «Велосипед» отменен",
+		},
+		{
+			name:        "disputed for buyer",
+			role:        domain.OrderRoleBuyer,
+			status:      domain.OrderStatusDisputed,
+			wantTitle:   "Открыт спор по заказу",
+			wantMessage: "По заказу «Велосипед» открыт спор",
+		},
+		{
+			name:        "disputed for seller",
+			role:        domain.OrderRoleSeller,
+			status:      domain.OrderStatusDisputed,
+			wantTitle:   "Открыт спор по заказу",
+			wantMessage: "По заказу «Велосипед» открыт спор",
+		},
+		{
+			name:        "unknown status",
+			role:        domain.OrderRoleBuyer,
+			status:      "returned",
+			wantTitle:   "Заказ обновлен",
+			wantMessage: "Статус заказа «Велосипед» изменился",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testOrderRequest(tt.status)
+			if tt.mutate != nil {
+				tt.mutate(&req)
+			}
+
+			title, message := describeOrderEvent(tt.role, req)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDescribeOrderEventSellerCancellationHasNoRefundNote(t *testing.T) {
+	_, message := describeOrderEvent(domain.OrderRoleSeller, testOrderRequest(domain.OrderStatusCancelled))
+	if strings.Contains(message, "деньги вернутся") {
+		t.Errorf("seller message %q should not mention a refund", message)
+	}
+}
+
+func TestNotifyOrderRejectsSameBuyerAndSeller(t *testing.T) {
+	uc := NewOrderNotificationUseCase(nil, nil)
+
+	req := testOrderRequest("")
+	req.Seller.UserID = req.Buyer.UserID
+
+	if _, err := uc.NotifyOrderCreated(context.Background(), req); !errors.Is(err, domain.ErrBuyerIsSeller) {
+		t.Errorf("NotifyOrderCreated() error = %v, want ErrBuyerIsSeller", err)
+	}
+
+	req.Status = domain.OrderStatusPaid
+	if _, err := uc.NotifyOrderStatusChanged(context.Background(), req); !errors.Is(err, domain.ErrBuyerIsSeller) {
+		t.Errorf("NotifyOrderStatusChanged() error = %v, want ErrBuyerIsSeller", err)
+	}
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+type OutboxRepository interface {
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxMessage, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+	Release(ctx context.Context, id uuid.UUID, availableAt time.Time, lastError string) error
+}
+
+// ChannelDeliverer доставляет уведомление по одному каналу и сам ведет его статус
+type ChannelDeliverer interface {
+	Deliver(ctx context.Context, notification *domain.Notification) error
+}
+
+var (
+	errNotReleased = errors.New("scheduled notification has not been released yet")
+)
+
+// OutboxDispatcher разбирает outbox: передает уведомления доставщикам каналов и публикует итоги доставки.
+// Запись outbox считается обработанной, как только уведомление перешло в свой жизненный цикл
+// (отправлено, запланирован повтор или окончательная ошибка) - дальше им занимается RetryWorker
+type OutboxDispatcher struct {
+	outboxRepo       OutboxRepository
+	notificationRepo NotificationRepository
+	channels         map[domain.DeliveryChannel]ChannelDeliverer
+	outcomes         OutcomePublisher
+	policy           RetryPolicy
+	pollInterval     time.Duration
+	batchSize        int
+	lockTimeout      time.Duration
+}
+
+func NewOutboxDispatcher(
+	outboxRepo OutboxRepository,
+	notificationRepo NotificationRepository,
+	outcomes OutcomePublisher,
+	policy RetryPolicy,
+	cfg *config.OutboxConfig,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo:       outboxRepo,
+		notificationRepo: notificationRepo,
+		channels:         make(map[domain.DeliveryChannel]ChannelDeliverer),
+		outcomes:         outcomes,
+		policy:           policy,
+		pollInterval:     cfg.PollInterval,
+		batchSize:        cfg.BatchSize,
+		lockTimeout:      cfg.LockTimeout,
+	}
+}
+
+func (d *OutboxDispatcher) RegisterChannel(channel domain.DeliveryChannel, deliverer ChannelDeliverer) {
+	d.channels[channel] = deliverer
+}
+
+func (d *OutboxDispatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Context cancelled, stopping outbox dispatcher...")
+			return nil
+		case <-ticker.C:
+			d.dispatchDue(ctx)
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatchDue(ctx context.Context) {
+	for {
+		messages, err := d.outboxRepo.Claim(ctx, time.Now().UTC(), d.lockTimeout, d.batchSize)
+		if err != nil {
+			log.Printf("Failed to claim outbox messages: %v", err)
+			return
+		}
+
+		for i := range messages {
+			d.dispatch(ctx, &messages[i])
+		}
+
+		if len(messages) < d.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context, message *domain.OutboxMessage) {
+	if message.IsOutcome() {
+		d.publish(ctx, message)
+		return
+	}
+
+	deliverer, ok := d.channels[message.Channel]
+	if !ok {
+		log.Printf("No deliverer for channel %s, dropping outbox message %s", message.Channel, message.Id)
+		d.markProcessed(ctx, message)
+		return
+	}
+
+	notification, err := d.notificationRepo.GetByID(ctx, message.NotificationID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			d.markProcessed(ctx, message)
+			return
+		}
+		d.release(ctx, message, err)
+		return
+	}
+
+	switch notification.Status {
+	case domain.StatusPending:
+		err = deliverer.Deliver(ctx, notification)
+	case domain.StatusScheduled:
+		// задача outbox стала доступна раньше, чем планировщик выпустил уведомление
+		d.release(ctx, message, errNotReleased)
+		return
+	case domain.StatusSending:
+		// предыдущий диспетчер упал посреди отправки (аренда истекла), результат неизвестен.
+		// Для at-least-once считаем попытку неудачной и отдаем уведомление retry воркеру
+		err = d.interrupted(ctx, notification)
+	default:
+		// уведомление уже отправлено, отменено или его доставкой занимается retry воркер
+		d.markProcessed(ctx, message)
+		return
+	}
+
+	if err != nil && !d.handedOver(ctx, notification.Id) {
+		d.release(ctx, message, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Delivery of notification %s via %s failed: %v", notification.Id, message.Channel, err)
+	}
+
+	d.markProcessed(ctx, message)
+}
+
+// publish отправляет событие об итоге доставки. При недоступности брокера запись возвращается в очередь,
+// поэтому событие может уйти повторно, но с тем же event_id
+func (d *OutboxDispatcher) publish(ctx context.Context, message *domain.OutboxMessage) {
+	notification, err := d.notificationRepo.GetByID(ctx, message.NotificationID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			d.markProcessed(ctx, message)
+			return
+		}
+		d.release(ctx, message, err)
+		return
+	}
+
+	if err := d.outcomes.PublishOutcome(ctx, domain.NewDeliveryOutcome(message, notification)); err != nil {
+		log.Printf("Failed to publish %s for notification %s: %v", message.Outcome, notification.Id, err)
+		d.release(ctx, message, err)
+		return
+	}
+
+	d.markProcessed(ctx, message)
+}
+
+// handedOver проверяет по базе, что после неудачной попытки уведомление ушло из pending/sending,
+// то есть его дальнейшей судьбой занимается retry воркер. Иначе доставку повторяет сам outbox
+func (d *OutboxDispatcher) handedOver(ctx context.Context, id uuid.UUID) bool {
+	current, err := d.notificationRepo.GetByID(ctx, id)
+	if err != nil {
+		return false
+	}
+	return current.Status != domain.StatusPending && current.Status != domain.StatusSending
+}
+
+func (d *OutboxDispatcher) interrupted(ctx context.Context, notification *domain.Notification) error {
+	cause := domain.NewTransientError(domain.ErrDeliveryInterrupted)
+	if err := notification.MarkRetrying(cause, time.Now().UTC()); err != nil {
+		return err
+	}
+	return d.notificationRepo.Update(ctx, notification)
+}
+
+func (d *OutboxDispatcher) markProcessed(ctx context.Context, message *domain.OutboxMessage) {
+	if err := d.outboxRepo.MarkProcessed(ctx, message.Id); err != nil {
+		// аренда истечет, и запись будет обработана повторно - это безопасно
+		log.Printf("Failed to mark outbox message %s as processed: %v", message.Id, err)
+	}
+}
+
+func (d *OutboxDispatcher) release(ctx context.Context, message *domain.OutboxMessage, cause error) {
+	availableAt := time.Now().UTC().Add(d.policy.Delay(message.Attempts))
+	if err := d.outboxRepo.Release(ctx, message.Id, availableAt, cause.Error()); err != nil {
+		log.Printf("Failed to release outbox message %s: %v", message.Id, err)
+	}
+}
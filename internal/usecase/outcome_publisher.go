@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+)
+
+// OutcomePublisher сообщает другим сервисам об итогах доставки уведомлений
+type OutcomePublisher interface {
+	PublishOutcome(ctx context.Context, outcome domain.DeliveryOutcome) error
+}
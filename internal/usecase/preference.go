@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+type PreferenceRepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.NotificationPreference, error)
+	DisabledChannels(ctx context.Context, userID uuid.UUID, notificationType domain.NotificationType) ([]domain.DeliveryChannel, error)
+	GetSettings(ctx context.Context, userID uuid.UUID) (*domain.DeliverySettings, error)
+	Update(ctx context.Context, settings *domain.DeliverySettings, preferences []domain.NotificationPreference) error
+}
+
+// DeliveryPlanner решает, по каким каналам и когда доставлять новое уведомление
+type DeliveryPlanner interface {
+	PlanDelivery(
+		ctx context.Context,
+		notification *domain.Notification,
+		channels ...domain.DeliveryChannel,
+	) (domain.PendingDelivery, error)
+}
+
+// ChannelFilter перепроверяет настройки перед самой отправкой: пользователь мог отключить
+// канал, пока уведомление ждало в outbox, очереди повторов или сводке
+type ChannelFilter interface {
+	ChannelEnabled(
+		ctx context.Context,
+		userID uuid.UUID,
+		notificationType domain.NotificationType,
+		channel domain.DeliveryChannel,
+	) (bool, error)
+}
+
+// PreferenceUseCase - управление настройками уведомлений через REST API
+// и проверка этих настроек перед постановкой уведомления в outbox
+type PreferenceUseCase struct {
+	preferenceRepo PreferenceRepository
+}
+
+func NewPreferenceUseCase(repo PreferenceRepository) *PreferenceUseCase {
+	return &PreferenceUseCase{
+		preferenceRepo: repo,
+	}
+}
+
+func (uc *PreferenceUseCase) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.PreferencesResponse, error) {
+	userID, err := resolveUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.preferenceRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	preferences, err := uc.preferenceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	response := model.NewPreferencesResponse(settings, preferences)
+	return &response, nil
+}
+
+func (uc *PreferenceUseCase) UpdatePreferences(
+	ctx context.Context,
+	req model.UpdatePreferencesRequest,
+) (*model.PreferencesResponse, error) {
+	userID, err := resolveUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	req.UserID = userID
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	preferences := make([]domain.NotificationPreference, 0, len(req.Preferences))
+	for _, update := range req.Preferences {
+		preferences = append(preferences, domain.NotificationPreference{
+			UserID:  userID,
+			Type:    update.Type,
+			Channel: update.Channel,
+			Enabled: update.Enabled,
+		})
+	}
+
+	var settings *domain.DeliverySettings
+	if req.ChangesSettings() {
+		settings, err = uc.preferenceRepo.GetSettings(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get preferences: %w", err)
+		}
+		req.ApplyTo(settings)
+	}
+
+	if err := uc.preferenceRepo.Update(ctx, settings, preferences); err != nil {
+		return nil, fmt.Errorf("failed to update preferences: %w", err)
+	}
+
+	return uc.GetPreferences(ctx, userID)
+}
+
+// PlanDelivery оставляет только разрешенные пользователем каналы, в режиме сводок убирает
+// отдельное письмо о частых уведомлениях в ближайшую сводку, а в тихие часы откладывает доставку
+// до конца окна. Само уведомление сохраняется всегда
+func (uc *PreferenceUseCase) PlanDelivery(
+	ctx context.Context,
+	notification *domain.Notification,
+	channels ...domain.DeliveryChannel,
+) (domain.PendingDelivery, error) {
+	channels, err := uc.allowedChannels(ctx, notification.UserID, notification.Type, channels)
+	if err != nil {
+		return domain.PendingDelivery{}, err
+	}
+
+	if len(channels) == 0 || notification.Type.IsUrgent() {
+		return domain.NewPendingDelivery(notification, channels...), nil
+	}
+
+	settings, err := uc.preferenceRepo.GetSettings(ctx, notification.UserID)
+	if err != nil {
+		return domain.PendingDelivery{}, fmt.Errorf("failed to check delivery settings: %w", err)
+	}
+
+	// отложенное уведомление проверяем на тихие часы и сводки в момент, когда оно будет выпущено
+	deliverAt := time.Now().UTC()
+	if notification.IsScheduledAfter(deliverAt) {
+		deliverAt = *notification.SendAt
+	}
+
+	if settings.Batches(notification.Type) && slices.Contains(channels, domain.ChannelEmail) {
+		digestAt := settings.NextDigestAt(deliverAt)
+		if until, quiet := settings.QuietUntil(digestAt); quiet {
+			digestAt = until
+		}
+		notification.DigestAt = &digestAt
+		channels = slices.DeleteFunc(slices.Clone(channels), func(channel domain.DeliveryChannel) bool {
+			return channel == domain.ChannelEmail
+		})
+	}
+
+	delivery := domain.NewPendingDelivery(notification, channels...)
+	if len(channels) == 0 {
+		return delivery, nil
+	}
+
+	if until, quiet := settings.QuietUntil(deliverAt); quiet {
+		delivery.DeliverAfter = until
+	}
+
+	return delivery, nil
+}
+
+func (uc *PreferenceUseCase) ChannelEnabled(
+	ctx context.Context,
+	userID uuid.UUID,
+	notificationType domain.NotificationType,
+	channel domain.DeliveryChannel,
+) (bool, error) {
+	channels, err := uc.allowedChannels(ctx, userID, notificationType, []domain.DeliveryChannel{channel})
+	if err != nil {
+		return false, err
+	}
+	return len(channels) > 0, nil
+}
+
+func (uc *PreferenceUseCase) allowedChannels(
+	ctx context.Context,
+	userID uuid.UUID,
+	notificationType domain.NotificationType,
+	channels []domain.DeliveryChannel,
+) ([]domain.DeliveryChannel, error) {
+	if len(channels) == 0 || notificationType.IsMandatory() {
+		return channels, nil
+	}
+
+	disabled, err := uc.preferenceRepo.DisabledChannels(ctx, userID, notificationType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check preferences: %w", err)
+	}
+
+	return slices.DeleteFunc(slices.Clone(channels), func(channel domain.DeliveryChannel) bool {
+		return slices.Contains(disabled, channel)
+	}), nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyCanRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	tests := []struct {
+		attempt int
+		want    bool
+	}{
+		{attempt: 0, want: true},
+		{attempt: 1, want: true},
+		{attempt: 2, want: true},
+		{attempt: 3, want: false},
+		{attempt: 4, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := policy.CanRetry(tt.attempt); got != tt.want {
+			t.Errorf("CanRetry(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay: 30 * time.Second,
+		MaxDelay:  5 * time.Minute,
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt uses base delay", attempt: 1, want: 30 * time.Second},
+		{name: "second attempt doubles", attempt: 2, want: time.Minute},
+		{name: "fourth attempt", attempt: 4, want: 4 * time.Minute},
+		{name: "capped by max delay", attempt: 5, want: 5 * time.Minute},
+		{name: "large attempt stays capped", attempt: 1000, want: 5 * time.Minute},
+		{name: "zero attempt uses base delay", attempt: 0, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Delay(tt.attempt); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		{
+			name:    "spread around exponential delay",
+			policy:  RetryPolicy{BaseDelay: 10 * time.Second, MaxDelay: time.Hour, Jitter: 0.2},
+			attempt: 3,
+			min:     32 * time.Second,
+			max:     48 * time.Second,
+		},
+		{
+			name:    "spread around max delay",
+			policy:  RetryPolicy{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, Jitter: 0.5},
+			attempt: 10,
+			min:     5 * time.Minute,
+			max:     15 * time.Minute,
+		},
+		{
+			name:    "full jitter never goes negative",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 1},
+			attempt: 1,
+			min:     0,
+			max:     2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 1000 {
+				got := tt.policy.Delay(tt.attempt)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("Delay(%d) = %v, want within [%v, %v]", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/config"
+)
+
+// RetryWorker периодически забирает уведомления, у которых подошло время повторной доставки.
+// Забор строк идет через FOR UPDATE SKIP LOCKED, поэтому воркеры нескольких реплик не пересекаются,
+// а аренда (LockTimeout) возвращает в работу попытки реплики, упавшей посреди отправки
+type RetryWorker struct {
+	notificationRepo NotificationRepository
+	delivery         *EmailDelivery
+	pollInterval     time.Duration
+	batchSize        int
+	lockTimeout      time.Duration
+}
+
+func NewRetryWorker(repo NotificationRepository, delivery *EmailDelivery, cfg *config.RetryConfig) *RetryWorker {
+	return &RetryWorker{
+		notificationRepo: repo,
+		delivery:         delivery,
+		pollInterval:     cfg.PollInterval,
+		batchSize:        cfg.BatchSize,
+		lockTimeout:      cfg.LockTimeout,
+	}
+}
+
+func (w *RetryWorker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Context cancelled, stopping retry worker...")
+			return nil
+		case <-ticker.C:
+			w.processDue(ctx)
+		}
+	}
+}
+
+func (w *RetryWorker) processDue(ctx context.Context) {
+	for {
+		notifications, err := w.notificationRepo.ClaimDueForRetry(ctx, time.Now().UTC(), w.lockTimeout, w.batchSize)
+		if err != nil {
+			log.Printf("Failed to claim notifications for retry: %v", err)
+			return
+		}
+
+		for i := range notifications {
+			if err := w.delivery.attempt(ctx, &notifications[i]); err != nil {
+				log.Printf("Retry of notification %s failed (attempt %d): %v",
+					notifications[i].Id, notifications[i].AttemptCount, err)
+			} else {
+				log.Printf("Notification %s delivered on attempt %d",
+					notifications[i].Id, notifications[i].AttemptCount)
+			}
+		}
+
+		if len(notifications) < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
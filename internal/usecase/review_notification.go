@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/domain"
+	model "github.com/squ1ky/avigo-c2c-marketplace/services/notification-service/internal/dto"
+)
+
+const reviewExcerptLength = 200
+
+// ReviewNotificationUseCase уведомляет продавца о новом отзыве покупателя
+type ReviewNotificationUseCase struct {
+	notificationRepo NotificationRepository
+	preferences      DeliveryPlanner
+}
+
+func NewReviewNotificationUseCase(repo NotificationRepository, preferences DeliveryPlanner) *ReviewNotificationUseCase {
+	return &ReviewNotificationUseCase{
+		notificationRepo: repo,
+		preferences:      preferences,
+	}
+}
+
+func (uc *ReviewNotificationUseCase) NotifyReviewReceived(
+	ctx context.Context,
+	req model.ReviewReceivedNotificationRequest,
+) (*domain.Notification, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	reviewerName := req.ReviewerName
+	if reviewerName == "" {
+		reviewerName = "Покупатель"
+	}
+	excerpt := truncateText(req.Text, reviewExcerptLength)
+
+	message := fmt.Sprintf("%s оценил(а) сделку на %d из %d", reviewerName, req.Rating, model.MaxReviewRating)
+	if req.ListingTitle != "" {
+		message = fmt.Sprintf("%s оценил(а) «%s» на %d из %d",
+			reviewerName, req.ListingTitle, req.Rating, model.MaxReviewRating)
+	}
+
+	metadata := domain.JSONB{
+		"review_id":      req.ReviewID,
+		"listing_id":     req.ListingID,
+		"listing_title":  req.ListingTitle,
+		"reviewer_name":  reviewerName,
+		"rating":         req.Rating,
+		"rating_stars":   ratingStars(req.Rating),
+		"review_excerpt": excerpt,
+		"deep_link":      fmt.Sprintf("/reviews/%s", req.ReviewID),
+		"email_template": "review_received",
+		"email_subject":  "Новый отзыв",
+	}
+	if req.ReviewerID != uuid.Nil {
+		metadata["reviewer_id"] = req.ReviewerID.String()
+	}
+	// Дополнительные поля события не должны перетирать служебные ключи
+	for key, value := range req.Metadata {
+		if _, reserved := metadata[key]; !reserved && key != "email" {
+			metadata[key] = value
+		}
+	}
+
+	notification := &domain.Notification{
+		UserID:   req.UserID,
+		EventID:  &req.EventID,
+		Type:     domain.TypeNewReview,
+		Title:    "Новый отзыв",
+		Message:  message,
+		Metadata: metadata,
+		SendAt:   req.SendAt,
+	}
+
+	var channels []domain.DeliveryChannel
+	if req.RecipientEmail != "" {
+		notification.Metadata["email"] = req.RecipientEmail
+		channels = append(channels, domain.ChannelEmail)
+	}
+
+	delivery, err := uc.preferences.PlanDelivery(ctx, notification, channels...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.notificationRepo.CreateWithOutbox(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func ratingStars(rating int) string {
+	return strings.Repeat("★", rating) + strings.Repeat("☆", model.MaxReviewRating-rating)
+}